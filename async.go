@@ -0,0 +1,220 @@
+package gologs
+
+import (
+	"io"
+	"reflect"
+	"sync/atomic"
+	"time"
+)
+
+// asyncJob 是交给后台 goroutine 写出的一行日志. flushed 不为 nil 时表示 Flush 的标记, 处理到它时关闭
+type asyncJob struct {
+	w       io.Writer
+	s       string
+	flush   bool
+	flushed chan struct{}
+}
+
+// asyncWriter 是异步模式下的写入队列, 由单个后台 goroutine 按顺序写出
+type asyncWriter struct {
+	ch   chan asyncJob
+	done chan struct{}
+	// abandoned 在等待写完超时 (见 DrainTimeout) 后置位, 剩余日志改由 flushAbandoned 写出, 后台 goroutine 随即退出
+	abandoned atomic.Bool
+	// busy 为后台 goroutine 正在写入的 writer, 超时后写出剩余日志时跳过它
+	busy atomic.Value
+}
+
+// busyWriter 包装 io.Writer 存入 atomic.Value, 保证每次存入的类型相同
+type busyWriter struct {
+	w io.Writer
+}
+
+// SetAsync 开启异步写入: 日志在调用方 goroutine 中格式化后放入容量为 bufferSize 的队列,
+// 由单个后台 goroutine 写到 writer、日志文件及各个 sink, 调用方不再等待 I/O.
+// 队列满时默认阻塞等待, 见 SetAsyncDropOnFull. Flush 等待队列写完, Close 时写完剩余日志并停止后台 goroutine (等待时间见 DrainTimeout).
+// bufferSize <= 0 写完队列后恢复同步写入. Console 系列方法与事件日志始终同步写入
+func (log *Logger) SetAsync(bufferSize int) {
+	log.asyncMu.Lock()
+	old, timeout := log.detachAsync(), log.drainTimeout
+	if bufferSize <= 0 {
+		log.asyncMu.Unlock()
+		log.waitAsync(old, timeout)
+		return
+	}
+
+	a := &asyncWriter{ch: make(chan asyncJob, bufferSize), done: make(chan struct{})}
+	a.busy.Store(busyWriter{})
+	log.async = a
+	log.asyncMu.Unlock()
+	log.waitAsync(old, timeout)
+	go func() {
+		defer close(a.done)
+		for job := range a.ch {
+			if job.flushed != nil {
+				close(job.flushed)
+				continue
+			}
+			if a.abandoned.Load() {
+				// 超时后剩余日志由 flushAbandoned 写出, 这里取到的一行无法再保证顺序, 丢弃后退出
+				log.asyncDropped.Add(1)
+				return
+			}
+			a.busy.Store(busyWriter{job.w})
+			writeLine(job.w, job.s, job.flush)
+			a.busy.Store(busyWriter{})
+			// 写日志文件失败的错误由 writeToFile 推迟报告, 后台 goroutine 不持有 mu, 写完即可报告
+			log.reportErrors()
+		}
+	}()
+}
+
+// SetAsyncDropOnFull 设置异步队列满时的行为: true 时直接丢弃日志并计数 (见 AsyncDropped), false 时阻塞等待 (默认)
+func (log *Logger) SetAsyncDropOnFull(drop bool) {
+	log.asyncDrop.Store(drop)
+}
+
+// DrainTimeout 设置 Close/StopAsync 等待异步队列写完的最长时间, 用于收到 SIGTERM 后在宽限期内退出.
+// 超时后由调用方 goroutine 尽量写出队列中剩余的日志, 最多再等待 d: 后台 goroutine 阻塞中的 writer 的日志
+// 直接丢弃 (计入 AsyncDropped), 其他 writer 与日志文件照常写出; 后台 goroutine 写完当前行后退出.
+// 因此 Close 最多等待约 2d. d <= 0 时一直等待直到写完 (默认)
+func (log *Logger) DrainTimeout(d time.Duration) {
+	log.asyncMu.Lock()
+	defer log.asyncMu.Unlock()
+	log.drainTimeout = d
+}
+
+// SetSyncForTesting 仅用于测试: true 时即使开启了异步模式, 每条日志也在日志调用返回前同步写出,
+// 测试无需 Flush 或轮询即可断言输出. 开启时先写完队列中已有的日志, 保证顺序不变
+func (log *Logger) SetSyncForTesting(b bool) {
+	if b {
+		log.Flush()
+	}
+	log.syncForTesting.Store(b)
+}
+
+// AsyncDropped 返回异步队列满时被丢弃的日志行数
+func (log *Logger) AsyncDropped() int64 {
+	return log.root().asyncDropped.Load()
+}
+
+// Flush 阻塞直到调用前进入异步队列的日志全部写出, 未开启异步模式时直接返回
+func (log *Logger) Flush() {
+	root := log.root()
+	root.asyncMu.RLock()
+	if root.async == nil {
+		root.asyncMu.RUnlock()
+		return
+	}
+	flushed := make(chan struct{})
+	root.async.ch <- asyncJob{flushed: flushed}
+	root.asyncMu.RUnlock()
+	<-flushed
+}
+
+// StopAsync 写完异步队列中的日志并停止后台 goroutine, 之后恢复同步写入. Close 时会自动调用
+func (log *Logger) StopAsync() {
+	log.asyncMu.Lock()
+	a, timeout := log.detachAsync(), log.drainTimeout
+	log.asyncMu.Unlock()
+	log.waitAsync(a, timeout)
+}
+
+// detachAsync 摘下当前的异步队列并关闭它, 返回值交给 waitAsync 等待写完. 调用方需持有 asyncMu 写锁.
+// 后台 goroutine 写出剩余日志时可能经错误处理函数再次写日志, 因此不能在持有 asyncMu 时等待
+func (log *Logger) detachAsync() *asyncWriter {
+	a := log.async
+	if a == nil {
+		return nil
+	}
+	log.async = nil
+	close(a.ch)
+	return a
+}
+
+// waitAsync 等待后台 goroutine 写完队列并退出, 最多等待 timeout (<= 0 时不限), a 为 nil 时直接返回.
+// 超时后用 flushAbandoned 尽量写出剩余日志, 同样最多等待 timeout
+func (log *Logger) waitAsync(a *asyncWriter, timeout time.Duration) {
+	if a == nil {
+		return
+	}
+	if timeout <= 0 {
+		<-a.done
+		return
+	}
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	select {
+	case <-a.done:
+		return
+	case <-timer.C:
+	}
+
+	a.abandoned.Store(true)
+	flushed := make(chan struct{})
+	go func() {
+		defer close(flushed)
+		log.flushAbandoned(a)
+	}()
+	timer.Reset(timeout)
+	select {
+	case <-flushed:
+	case <-timer.C:
+	}
+}
+
+// flushAbandoned 写出超时后队列中剩余的日志. 写往后台 goroutine 阻塞中的 writer 的日志同样会阻塞, 直接丢弃
+func (log *Logger) flushAbandoned(a *asyncWriter) {
+	stalled := a.busy.Load().(busyWriter).w
+	for job := range a.ch {
+		if job.flushed != nil {
+			close(job.flushed)
+			continue
+		}
+		if sameWriter(job.w, stalled) {
+			log.asyncDropped.Add(1)
+			continue
+		}
+		writeLine(job.w, job.s, job.flush)
+		log.reportErrors()
+	}
+}
+
+// sameWriter 判断 a 与 b 是否为同一个 writer, 不可比较的类型视为不同
+func sameWriter(a, b io.Writer) bool {
+	if a == nil || b == nil {
+		return false
+	}
+	t := reflect.TypeOf(a)
+	return t == reflect.TypeOf(b) && t.Comparable() && a == b
+}
+
+// write 写出一行日志, 异步模式下放入队列由后台 goroutine 写出
+func (log *Logger) write(w io.Writer, s string) {
+	// 写日志文件失败时错误处理函数在后台 goroutine 中执行, 它写的日志若进入队列,
+	// 队列满时后台 goroutine 会等待自己而死锁, 因此错误处理期间的日志直接同步写出
+	// SetSyncForTesting 开启时同样直接同步写出
+	if log.inErrorHandler.Load() || log.syncForTesting.Load() {
+		writeLine(w, s, log.lineBuffered)
+		return
+	}
+	log.asyncMu.RLock()
+	a := log.async
+	if a == nil {
+		log.asyncMu.RUnlock()
+		writeLine(w, s, log.lineBuffered)
+		return
+	}
+	defer log.asyncMu.RUnlock()
+
+	job := asyncJob{w: w, s: s, flush: log.lineBuffered}
+	if !log.asyncDrop.Load() {
+		a.ch <- job
+		return
+	}
+	select {
+	case a.ch <- job:
+	default:
+		log.asyncDropped.Add(1)
+	}
+}
@@ -0,0 +1,194 @@
+package gologs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// blockingWriter 在 release 关闭前阻塞所有写入
+type blockingWriter struct {
+	release chan struct{}
+	buf     syncBuffer
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	return w.buf.Write(p)
+}
+
+func TestLogger_SetAsync(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "async.log")
+	var buf syncBuffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetFile(filename)
+	log.SetIsLogToFile(true)
+	log.SetAsync(16)
+
+	var want strings.Builder
+	for i := 0; i < 100; i++ {
+		log.Warnf("line %d", i)
+		fmt.Fprintf(&want, "[Warn] line %d \n", i)
+	}
+	log.Flush()
+	if got := buf.String(); got != want.String() {
+		t.Fatalf("expected all lines in order after Flush, got %q", got)
+	}
+
+	log.Warn("last")
+	log.Close()
+	want.WriteString("[Warn] last \n")
+	if got, _ := os.ReadFile(filename); string(got) != want.String() {
+		t.Fatalf("expected Close to drain the queue into the file, got %q", got)
+	}
+}
+
+func TestLogger_SetAsyncDropOnFull(t *testing.T) {
+	w := &blockingWriter{release: make(chan struct{})}
+	log := NewLogger(Debug)
+	log.SetOutput(w)
+	log.SetAsync(2)
+	log.SetAsyncDropOnFull(true)
+
+	// 后台 goroutine 阻塞在第一行, 队列最多再容纳 2 行
+	for i := 0; i < 10; i++ {
+		log.Warnf("line %d", i)
+	}
+	close(w.release)
+	log.Flush()
+
+	if dropped := log.AsyncDropped(); dropped < 7 || dropped > 8 {
+		t.Fatalf("expected 7-8 dropped lines, got %d", dropped)
+	}
+	if lines := strings.Count(w.buf.String(), "\n"); int64(lines)+log.AsyncDropped() != 10 {
+		t.Fatalf("expected written and dropped lines to add up, got %d written", lines)
+	}
+	log.Close()
+}
+
+func TestLogger_SetAsyncConcurrent(t *testing.T) {
+	var buf syncBuffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetAsync(4)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 4; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				log.Warn("concurrent")
+			}
+		}()
+	}
+	wg.Wait()
+	log.StopAsync()
+
+	if got := strings.Count(buf.String(), "[Warn] concurrent \n"); got != 200 {
+		t.Fatalf("expected 200 lines with blocking policy, got %d", got)
+	}
+	log.Warn("sync again")
+	if !strings.HasSuffix(buf.String(), "[Warn] sync again \n") {
+		t.Fatal("expected synchronous writes after StopAsync")
+	}
+}
+
+func TestLogger_SetAsyncErrorHandlerLogs(t *testing.T) {
+	for _, size := range []int{1, 1024} {
+		log := newBrokenFileLogger(t)
+		var buf syncBuffer
+		log.SetOutput(&buf)
+		// handler 自身写日志, 写文件失败时它在后台 goroutine 中执行, 不能等待自己的队列
+		log.SetErrorHandler(func(err error) {
+			log.Warnf("log write failed: %v", err)
+		})
+		log.SetAsync(size)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 10; i++ {
+				log.Warnf("line %d", i)
+			}
+			log.Close()
+		}()
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("SetAsync(%d): expected Close to return when the error handler logs", size)
+		}
+		if !strings.Contains(buf.String(), "log write failed") {
+			t.Fatalf("SetAsync(%d): expected the handler's line in output, got %q", size, buf.String())
+		}
+	}
+}
+
+func TestLogger_DrainTimeout(t *testing.T) {
+	var buf syncBuffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetAsync(64)
+	log.DrainTimeout(time.Second)
+	for i := 0; i < 50; i++ {
+		log.Warnf("line %d", i)
+	}
+	log.StopAsync()
+	if lines := strings.Count(buf.String(), "\n"); lines != 50 {
+		t.Fatalf("expected the queue to drain within the timeout, got %d lines", lines)
+	}
+
+	filename := filepath.Join(t.TempDir(), "drain.log")
+	w := &blockingWriter{release: make(chan struct{})}
+	log.SetOutput(w)
+	log.SetFile(filename)
+	log.SetIsLogToFile(true)
+	log.SetAsync(64)
+	log.DrainTimeout(50 * time.Millisecond)
+	for i := 0; i < 10; i++ {
+		log.Warnf("line %d", i)
+	}
+	a := log.async
+
+	start := time.Now()
+	log.Close()
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected Close to return after the drain timeout, took %v", elapsed)
+	}
+
+	// 超时后剩余日志尽量写出: 日志文件不受阻塞的 writer 影响, 全部写出
+	if got, _ := os.ReadFile(filename); strings.Count(string(got), "\n") != 10 {
+		t.Fatalf("expected the remaining lines to be flushed to the file, got %q", got)
+	}
+	// 阻塞中的 writer 只写出阻塞的那一行, 其余计入丢弃数
+	close(w.release)
+	<-a.done
+	if lines := strings.Count(w.buf.String(), "\n"); lines != 1 {
+		t.Fatalf("expected only the stalled line on the blocked writer, got %d", lines)
+	}
+	if dropped := log.AsyncDropped(); dropped != 9 {
+		t.Fatalf("expected 9 dropped lines for the blocked writer, got %d", dropped)
+	}
+}
+
+func TestLogger_SetSyncForTesting(t *testing.T) {
+	w := &blockingWriter{release: make(chan struct{})}
+	close(w.release)
+	log := NewLogger(Debug)
+	log.SetOutput(w)
+	log.SetAsync(16)
+	log.SetSyncForTesting(true)
+
+	for i := 0; i < 10; i++ {
+		log.Warnf("line %d", i)
+		if want := fmt.Sprintf("[Warn] line %d \n", i); !strings.HasSuffix(w.buf.String(), want) {
+			t.Fatalf("expected %q right after the call without Flush, got %q", want, w.buf.String())
+		}
+	}
+	log.Close()
+}
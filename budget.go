@@ -0,0 +1,54 @@
+package gologs
+
+// SetMaxTotalBytes 限制本次运行输出的日志总字节数, 所有 sink 的写入合计 (按默认格式渲染的行长度计算).
+// 超出后输出一条 "log budget exceeded" 提示, 之后的日志全部丢弃并计数. n <= 0 表示不限制 (默认).
+// 调用时会重置已统计的字节数和丢弃计数
+func (log *Logger) SetMaxTotalBytes(n int64) {
+	log.budgetMu.Lock()
+	defer log.budgetMu.Unlock()
+	log.maxTotalBytes = n
+	log.totalBytes = 0
+	log.budgetDropped = 0
+	log.budgetExceeded = false
+}
+
+// BudgetDropped 返回因超出 SetMaxTotalBytes 而丢弃的日志条数
+func (log *Logger) BudgetDropped() int64 {
+	root := log.root()
+	root.budgetMu.Lock()
+	defer root.budgetMu.Unlock()
+	return root.budgetDropped
+}
+
+// withinBudget 为日志条目预留字节预算, 预算不足时返回 false. 第一次超出时输出提示
+func (log *Logger) withinBudget(sinks []Sink, e *LogEntry, line string) bool {
+	log.budgetMu.Lock()
+	if log.maxTotalBytes <= 0 {
+		log.budgetMu.Unlock()
+		return true
+	}
+	if log.budgetExceeded {
+		log.budgetDropped++
+		log.budgetMu.Unlock()
+		return false
+	}
+
+	var n int64
+	for _, sink := range sinks {
+		if e.Level >= sink.MinLevel {
+			n += int64(len(line))
+		}
+	}
+	if log.totalBytes+n <= log.maxTotalBytes {
+		log.totalBytes += n
+		log.budgetMu.Unlock()
+		return true
+	}
+	log.budgetExceeded = true
+	log.budgetDropped++
+	log.budgetMu.Unlock()
+
+	notice := log.newEntry(log.now(), Error, nil, "log budget exceeded, further logs are dropped")
+	log.writeEntry(sinks, notice, log.render(notice))
+	return false
+}
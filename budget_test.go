@@ -0,0 +1,43 @@
+package gologs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLogger_MaxTotalBytes(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+
+	line := "[Warn] 0123456789 \n"
+	log.SetMaxTotalBytes(int64(len(line) * 3))
+	for i := 0; i < 6; i++ {
+		log.Warn("0123456789")
+	}
+
+	want := strings.Repeat(line, 3) + "[Error] log budget exceeded, further logs are dropped \n"
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected output:\n%q\nwant:\n%q", got, want)
+	}
+	if got := log.BudgetDropped(); got != 3 {
+		t.Fatalf("expected 3 dropped entries, got %d", got)
+	}
+}
+
+func TestLogger_MaxTotalBytesCountsAllSinks(t *testing.T) {
+	var main, extra bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&main)
+	log.AddSink(Sink{Writer: &extra})
+
+	line := "[Warn] x \n"
+	log.SetMaxTotalBytes(int64(len(line) * 3))
+	log.Warn("x")
+	log.Warn("x")
+
+	if got := main.String(); got != line+"[Error] log budget exceeded, further logs are dropped \n" {
+		t.Fatalf("expected budget to be shared across sinks, got %q", got)
+	}
+}
@@ -0,0 +1,52 @@
+package gologs
+
+import (
+	"path/filepath"
+	"runtime"
+	"strconv"
+)
+
+// Caller 描述一条日志的调用位置
+type Caller struct {
+	File     string
+	Line     int
+	Function string
+}
+
+// SetReportCaller 开启后记录每条日志的调用位置 (调用日志方法的用户代码).
+// JSON 输出中为 caller 对象 {"file":...,"line":...,"function":...}, 文本模板中的 {{caller}} 替换为 "main.go:42"
+func (log *Logger) SetReportCaller(b bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.reportCaller = b
+}
+
+// callerAt 返回调用 callerAt 的函数往上 skip 层的调用位置
+func callerAt(skip int) *Caller {
+	pc, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return nil
+	}
+	c := &Caller{File: file, Line: line}
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		c.Function = fn.Name()
+	}
+	return c
+}
+
+// short 返回 "文件名:行号" 形式的调用位置, c 为 nil 时返回空字符串
+func (c *Caller) short() string {
+	if c == nil {
+		return ""
+	}
+	return filepath.Base(c.File) + ":" + strconv.Itoa(c.Line)
+}
+
+// callerFromPC 根据程序计数器返回调用位置, 用于调用位置由外部记录的场景 (如 slog.Record.PC)
+func callerFromPC(pc uintptr) *Caller {
+	frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+	if frame.File == "" {
+		return nil
+	}
+	return &Caller{File: frame.File, Line: frame.Line, Function: frame.Function}
+}
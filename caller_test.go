@@ -0,0 +1,107 @@
+package gologs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// currentLine 返回调用处的行号
+func currentLine() int {
+	_, _, line, _ := runtime.Caller(1)
+	return line
+}
+
+func TestLogger_JSONCaller(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetJSON(true)
+	log.SetReportCaller(true)
+
+	line := currentLine() + 1
+	log.Warn("here")
+
+	var obj struct {
+		Caller struct {
+			File     string `json:"file"`
+			Line     int    `json:"line"`
+			Function string `json:"function"`
+		} `json:"caller"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &obj); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(obj.Caller.File, "caller_test.go") || obj.Caller.Line != line {
+		t.Fatalf("expected caller_test.go:%d, got %s:%d", line, obj.Caller.File, obj.Caller.Line)
+	}
+	if !strings.HasSuffix(obj.Caller.Function, "TestLogger_JSONCaller") {
+		t.Fatalf("unexpected caller function %q", obj.Caller.Function)
+	}
+}
+
+func TestLogger_CallerEntryPoints(t *testing.T) {
+	log := NewLogger(Debug)
+	log.SetOutput(&bytes.Buffer{})
+	log.SetReportCaller(true)
+	log.SetErrorBufferSize(10)
+
+	l1 := currentLine() + 1
+	log.Error("a")
+	l2 := currentLine() + 1
+	log.Errorf("%s", "b")
+	l3 := currentLine() + 1
+	log.Logf(Error, "%s", "c")
+	l4 := currentLine() + 1
+	log.LogAt(time.Now(), Error, "d")
+	l5 := currentLine() + 1
+	log.Named("child").Error("e")
+
+	entries := log.RecentErrors()
+	for i, want := range []int{l1, l2, l3, l4, l5} {
+		c := entries[i].Caller
+		if c == nil || !strings.HasSuffix(c.File, "caller_test.go") || c.Line != want {
+			t.Fatalf("entry %d: expected caller_test.go:%d, got %+v", i, want, c)
+		}
+	}
+}
+
+func TestLogger_CallerToken(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetFormatter(map[LogLevel]string{Warn: "[Warn] ({{caller}}) %s\n"})
+
+	log.Warn("off")
+	if got := buf.String(); got != "[Warn] () off\n" {
+		t.Fatalf("expected empty caller when reporting is off, got %q", got)
+	}
+
+	log.SetReportCaller(true)
+	var lines []int
+	buf.Reset()
+	lines = append(lines, currentLine()+1)
+	log.Warn("direct")
+	lines = append(lines, currentLine()+1)
+	log.Logf(Warn, "%s", "logf")
+	lines = append(lines, currentLine()+1)
+	log.WithFields(map[string]interface{}{"k": 1}).Warn("entry")
+	lines = append(lines, currentLine()+1)
+	log.WarnFields("fields", nil)
+	lines = append(lines, currentLine()+1)
+	log.KV(Warn, map[string]interface{}{"k": 1})
+
+	got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(got) != len(lines) {
+		t.Fatalf("expected %d lines, got %q", len(lines), buf.String())
+	}
+	for i, line := range lines {
+		if want := fmt.Sprintf("[Warn] (caller_test.go:%d) ", line); !strings.HasPrefix(got[i], want) {
+			t.Fatalf("line %d: expected prefix %q, got %q", i, want, got[i])
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package gologs
+
+import "time"
+
+// maxEveryKeys 为 WarnEvery 记录的 key 数量上限
+const maxEveryKeys = 1024
+
+// SetClock 设置 Logger 获取当前时间的函数, 用于日志时间戳和 WarnEvery 等按时间判断的功能,
+// 主要供测试注入固定时钟. 传入 nil 恢复 time.Now
+func (log *Logger) SetClock(clock func() time.Time) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.clock = clock
+}
+
+// SetTimeLocation 设置日志时间所在的时区, 影响默认后缀中的时间、JSON 的 time 字段以及按天轮转的日期.
+// 传入 nil 恢复本地时间 (默认)
+func (log *Logger) SetTimeLocation(loc *time.Location) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.location = loc
+}
+
+// SetTimeFormat 设置文本模板中 {{time}} 的格式 (time.Format 的 layout), 为空时与默认后缀相同, 为 "2006-01-02 15:04.05"
+func (log *Logger) SetTimeFormat(layout string) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.timeFormat = layout
+}
+
+// timeText 按 SetTimeFormat 的设置格式化 t
+func (log *Logger) timeText(t time.Time) string {
+	if log.timeFormat == "" {
+		return formatCurtime(t)
+	}
+	return t.Format(log.timeFormat)
+}
+
+// now 返回当前时间, 已转换到 SetTimeLocation 设置的时区
+func (log *Logger) now() time.Time {
+	if log.clock != nil {
+		return log.inLocation(log.clock())
+	}
+	return log.inLocation(time.Now())
+}
+
+// clockNow 在读锁下返回当前时间, 供不经过日志核心入口的调用使用
+func (log *Logger) clockNow() time.Time {
+	log.mu.RLock()
+	defer log.mu.RUnlock()
+	return log.now()
+}
+
+func (log *Logger) inLocation(t time.Time) time.Time {
+	if log.location != nil {
+		return t.In(log.location)
+	}
+	return t
+}
+
+// WarnEvery 以 Warn 等级输出格式化日志, 同一个 key 在 interval 内最多输出一次, 用于周期性提醒持续存在的问题.
+// 最多记录 maxEveryKeys 个 key, 超出时先清理已过期的 key, 仍不足时淘汰最早输出的 key
+func (log *Logger) WarnEvery(key string, interval time.Duration, format string, s ...interface{}) {
+	if !log.root().allowEvery(key, interval) {
+		return
+	}
+	log.logInterfacef(nil, Warn, nil, format, s...)
+}
+
+func (log *Logger) allowEvery(key string, interval time.Duration) bool {
+	now := log.clockNow()
+	log.everyMu.Lock()
+	defer log.everyMu.Unlock()
+
+	if last, ok := log.everyAt[key]; ok && now.Sub(last) < interval {
+		return false
+	}
+	if log.everyAt == nil {
+		log.everyAt = make(map[string]time.Time)
+	}
+	if _, ok := log.everyAt[key]; !ok && len(log.everyAt) >= maxEveryKeys {
+		log.evictEvery(now, interval)
+	}
+	log.everyAt[key] = now
+	return true
+}
+
+func (log *Logger) evictEvery(now time.Time, interval time.Duration) {
+	var oldestKey string
+	var oldest time.Time
+	for k, at := range log.everyAt {
+		if now.Sub(at) >= interval {
+			delete(log.everyAt, k)
+			continue
+		}
+		if oldestKey == "" || at.Before(oldest) {
+			oldestKey, oldest = k, at
+		}
+	}
+	if len(log.everyAt) >= maxEveryKeys {
+		delete(log.everyAt, oldestKey)
+	}
+}
@@ -0,0 +1,112 @@
+package gologs
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogger_WarnEvery(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	log.SetClock(func() time.Time { return now })
+
+	emitted := 0
+	for step := 0; step < 10; step++ {
+		buf.Reset()
+		log.WarnEvery("disk", 3*time.Second, "disk almost full (%d)", step)
+		if buf.Len() > 0 {
+			emitted++
+			if step%3 != 0 {
+				t.Fatalf("unexpected emission at step %d: %q", step, buf.String())
+			}
+		}
+		now = now.Add(time.Second)
+	}
+	if emitted != 4 {
+		t.Fatalf("expected emissions at 0s, 3s, 6s, 9s, got %d", emitted)
+	}
+
+	// 不同的 key 互不影响
+	buf.Reset()
+	log.WarnEvery("cpu", 3*time.Second, "cpu hot")
+	if !strings.Contains(buf.String(), "cpu hot") {
+		t.Fatalf("expected independent key to emit, got %q", buf.String())
+	}
+}
+
+func TestLogger_WarnEveryBoundedKeys(t *testing.T) {
+	log := NewLogger(Debug)
+	log.SetOutput(&bytes.Buffer{})
+	for i := 0; i < maxEveryKeys*2; i++ {
+		log.WarnEvery(fmt.Sprint(i), time.Hour, "x")
+	}
+	if n := len(log.everyAt); n > maxEveryKeys {
+		t.Fatalf("expected at most %d tracked keys, got %d", maxEveryKeys, n)
+	}
+}
+
+func TestLogger_SetTimeLocation(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetClock(func() time.Time { return time.Date(2024, 1, 1, 23, 30, 0, 0, time.UTC) })
+	log.SetTimeLocation(time.FixedZone("UTC+8", 8*3600))
+
+	log.Info("text")
+	if got := buf.String(); got != "[-] text , 2024-01-02 07:30.00\n" {
+		t.Fatalf("expected suffix time in the configured zone, got %q", got)
+	}
+
+	buf.Reset()
+	log.LogAt(time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC), Info, "explicit")
+	if got := buf.String(); got != "[-] explicit , 2024-06-01 08:00.00\n" {
+		t.Fatalf("expected explicit time converted, got %q", got)
+	}
+
+	buf.Reset()
+	log.SetJSON(true)
+	log.Warn("json")
+	if !strings.HasPrefix(buf.String(), `{"time":"2024-01-02T07:30:00+08:00"`) {
+		t.Fatalf("expected json time in the configured zone, got %q", buf.String())
+	}
+}
+
+func TestLogger_WarnEveryWithConcurrentSetClock(t *testing.T) {
+	log := NewLogger(Debug)
+	log.SetOutput(&syncBuffer{})
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			log.SetClock(time.Now)
+			log.SetTimeLocation(time.UTC)
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		log.WarnEvery("tick", time.Hour, "tick")
+	}
+	<-done
+}
+
+func TestLogger_FormatWithConcurrentSetClock(t *testing.T) {
+	log := NewLogger(Debug)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			log.SetClock(time.Now)
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		log.Format(Info, "tick")
+	}
+	<-done
+}
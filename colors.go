@@ -1,5 +1,12 @@
 package gologs
 
+import (
+	"io"
+	"os"
+	"regexp"
+	"strings"
+)
+
 //颜色部分:
 
 func Black(s string) string {
@@ -97,3 +104,87 @@ func WhiteBold(s string) string {
 func WhiteLine(s string) string {
 	return "\033[4;37m" + s + "\033[0m"
 }
+
+// colorResetSeq 是颜色函数附加在结尾的重置序列
+const colorResetSeq = "\033[0m"
+
+// SetColorReset 设置彩色输出结尾的重置序列, 替换颜色函数默认附加的 "\033[0m" (完全重置会清除部分终端的背景设置).
+// 传入空字符串表示不输出重置序列, 便于与其他颜色处理组合. 只替换以默认重置序列结尾的彩色行
+func (log *Logger) SetColorReset(seq string) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.resetSeq = seq
+	log.hasResetSeq = true
+}
+
+// colorReset 将彩色行 s 结尾的默认重置序列替换为 SetColorReset 设置的序列
+func (log *Logger) colorReset(s string) string {
+	if !log.hasResetSeq || !strings.HasSuffix(s, colorResetSeq) {
+		return s
+	}
+	return strings.TrimSuffix(s, colorResetSeq) + log.resetSeq
+}
+
+// isTerminal 判断 w 是否为终端. 只识别 *os.File, 以字符设备判断, 不依赖 golang.org/x/term
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// isRegularFile 判断 w 是否为普通文件 (而不是终端、管道等)
+func isRegularFile(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode().IsRegular()
+}
+
+// OSC 8 超链接:
+
+// Hyperlink 返回用 OSC 8 转义序列包裹的可点击文本, 支持的终端中点击 text 会打开 url.
+// 未开启颜色时 (非终端输出、日志文件等) Logger 会去掉这些转义序列, 只保留 text
+func Hyperlink(url, text string) string {
+	return "\033]8;;" + url + "\033\\" + text + "\033]8;;\033\\"
+}
+
+var hyperlinkPattern = regexp.MustCompile("\033\\]8;[^\033\a]*;[^\033\a]*(?:\033\\\\|\a)")
+
+func stripHyperlinks(s string) string {
+	if !strings.Contains(s, "\033]8;") {
+		return s
+	}
+	return hyperlinkPattern.ReplaceAllString(s, "")
+}
+
+// 按内容着色:
+
+type contentColorRule struct {
+	pattern *regexp.Regexp
+	color   func(string) string
+}
+
+// AddContentColorRule 添加按消息内容着色的规则, 消息匹配 pattern 时整行使用 color 代替等级颜色.
+// 规则按添加顺序匹配, 第一个匹配的规则生效, 只在开启颜色的输出上起作用
+func (log *Logger) AddContentColorRule(pattern *regexp.Regexp, color func(string) string) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	rules := make([]contentColorRule, len(log.contentColors), len(log.contentColors)+1)
+	copy(rules, log.contentColors)
+	log.contentColors = append(rules, contentColorRule{pattern, color})
+}
+
+// contentColor 返回第一个匹配 msg 的规则的颜色, 没有匹配时返回 nil
+func (log *Logger) contentColor(msg string) func(string) string {
+	for _, rule := range log.contentColors {
+		if rule.pattern.MatchString(msg) {
+			return rule.color
+		}
+	}
+	return nil
+}
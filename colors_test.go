@@ -0,0 +1,256 @@
+package gologs
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestHyperlink(t *testing.T) {
+	link := Hyperlink("file:///tmp/a.log", "a.log")
+	if link != "\033]8;;file:///tmp/a.log\033\\a.log\033]8;;\033\\" {
+		t.Fatalf("unexpected hyperlink: %q", link)
+	}
+
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+
+	log.SetColor(true)
+	log.Warn("saved to " + link)
+	if !strings.Contains(buf.String(), link) {
+		t.Fatalf("expected OSC 8 sequence with color enabled, got %q", buf.String())
+	}
+
+	buf.Reset()
+	log.SetColor(false)
+	log.Warn("saved to " + link)
+	if got := buf.String(); got != "[Warn] saved to a.log \n" {
+		t.Fatalf("expected plain link text without color, got %q", got)
+	}
+}
+
+func TestLogger_OverrideColor(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetColor(true)
+
+	log.OverrideColor(Warn, Green)
+
+	log.Warn("w")
+	if got := buf.String(); got != Green("[Warn] w \n") {
+		t.Fatalf("expected overridden color, got %q", got)
+	}
+
+	buf.Reset()
+	log.Error("e")
+	if got := buf.String(); got != RedBold("[Error] e \n") {
+		t.Fatalf("expected other levels unchanged, got %q", got)
+	}
+
+	if got := DefaultColorMap[Warn]("x"); got != YellowBold("x") {
+		t.Fatalf("default color map was modified: %q", got)
+	}
+}
+
+func TestLogger_SetColorForcesColorOnPipe(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+
+	log := NewLogger(Debug)
+	log.SetOutput(w)
+	log.SetColor(true)
+	log.Warn("piped")
+	w.Close()
+
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != YellowBold("[Warn] piped \n") {
+		t.Fatalf("expected color on non-tty writer, got %q", got)
+	}
+}
+
+func TestLogger_LogColored(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+
+	log.LogColored(Warn, Purple, "plain")
+	if got := buf.String(); got != "[Warn] plain \n" {
+		t.Fatalf("expected no color when color is off, got %q", got)
+	}
+
+	buf.Reset()
+	log.SetColor(true)
+	log.LogColored(Warn, Purple, "custom")
+	if got := buf.String(); got != Purple("[Warn] custom \n") {
+		t.Fatalf("expected custom color, got %q", got)
+	}
+
+	buf.Reset()
+	log.Warn("normal")
+	if got := buf.String(); got != YellowBold("[Warn] normal \n") {
+		t.Fatalf("expected level color on next call, got %q", got)
+	}
+}
+
+func TestLogger_AddContentColorRule(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetColor(true)
+	log.AddContentColorRule(regexp.MustCompile(`(?i)deprecated`), Yellow)
+	log.AddContentColorRule(regexp.MustCompile(`api`), Purple)
+
+	log.Warn("Deprecated api called")
+	if got := buf.String(); got != Yellow("[Warn] Deprecated api called \n") {
+		t.Fatalf("expected first matching rule color, got %q", got)
+	}
+
+	buf.Reset()
+	log.Error("disk full")
+	if got := buf.String(); got != RedBold("[Error] disk full \n") {
+		t.Fatalf("expected level color for non-matching message, got %q", got)
+	}
+
+	buf.Reset()
+	log.SetColor(false)
+	log.Warn("deprecated")
+	if got := buf.String(); got != "[Warn] deprecated \n" {
+		t.Fatalf("expected no color when color is off, got %q", got)
+	}
+}
+
+func TestLogger_SetColorAuto(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetColorAuto()
+	log.Warn("buffer")
+	if got := buf.String(); got != "[Warn] buffer \n" {
+		t.Fatalf("expected no color for non-file writer, got %q", got)
+	}
+
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	log.SetOutput(f)
+	if log.Color {
+		t.Fatal("expected no color for regular file")
+	}
+
+	// /dev/null 是字符设备, 与终端一样被识别为 TTY
+	if devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0); err == nil {
+		defer devNull.Close()
+		if info, _ := devNull.Stat(); info.Mode()&os.ModeCharDevice != 0 {
+			log.SetOutput(devNull)
+			if !log.Color {
+				t.Fatal("expected color for character device")
+			}
+		}
+	}
+
+	log.SetColor(true)
+	log.SetOutput(&buf)
+	buf.Reset()
+	log.Warn("forced")
+	if got := buf.String(); got != YellowBold("[Warn] forced \n") {
+		t.Fatalf("expected SetColor to override auto detection, got %q", got)
+	}
+}
+
+func TestLogger_FWritersStripColorForFiles(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "explicit.log")
+	f, err := os.Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetColor(true)
+
+	log.FWarnf(f, "to %s", "file")
+	log.FLogf(f, Error, "plain")
+	f.Close()
+	if got, _ := os.ReadFile(filename); string(got) != "[Warn] to file \n[Error] plain\n \n" {
+		t.Fatalf("expected no color codes in file, got %q", got)
+	}
+
+	log.FWarnf(&buf, "to %s", "buffer")
+	if got := buf.String(); got != YellowBold("[Warn] to buffer \n") {
+		t.Fatalf("expected color kept for non-file writers, got %q", got)
+	}
+}
+
+func TestLogger_SetColorReset(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetColor(true)
+
+	log.Warn("default")
+	if got := buf.String(); !strings.HasSuffix(got, "\033[0m") {
+		t.Fatalf("expected the default reset sequence, got %q", got)
+	}
+
+	buf.Reset()
+	log.SetColorReset("\033[39m")
+	log.Warn("foreground")
+	log.LogColored(Warn, Red, "colored")
+	for _, line := range strings.SplitAfter(buf.String(), "\033[39m")[:2] {
+		if strings.Contains(line, "\033[0m") || !strings.HasSuffix(line, "\033[39m") {
+			t.Fatalf("expected the configured reset sequence, got %q", buf.String())
+		}
+	}
+
+	buf.Reset()
+	log.SetColorReset("")
+	log.Warn("none")
+	if got := buf.String(); got != "\033[1;33m[Warn] none \n" {
+		t.Fatalf("expected no reset sequence, got %q", got)
+	}
+}
+
+func TestLogger_SetColorForcePipe(t *testing.T) {
+	var primary, extra bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&primary)
+	log.SetColorAuto()
+	log.AddOutput(&extra)
+
+	log.Warn("auto")
+	if primary.String() != "[Warn] auto \n" || extra.String() != "[Warn] auto \n" {
+		t.Fatalf("expected no color on non-tty writers with auto-detect, got %q / %q", primary.String(), extra.String())
+	}
+
+	primary.Reset()
+	extra.Reset()
+	log.SetColorForcePipe(true)
+	log.Warn("forced")
+	want := YellowBold("[Warn] forced \n")
+	if primary.String() != want || extra.String() != want {
+		t.Fatalf("expected color on non-tty writers with force-pipe, got %q / %q", primary.String(), extra.String())
+	}
+
+	// 关闭后恢复自动检测
+	primary.Reset()
+	extra.Reset()
+	log.SetColorForcePipe(false)
+	log.Warn("auto")
+	if primary.String() != "[Warn] auto \n" || extra.String() != "[Warn] auto \n" {
+		t.Fatalf("expected auto-detect after disabling force-pipe, got %q / %q", primary.String(), extra.String())
+	}
+}
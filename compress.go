@@ -0,0 +1,57 @@
+package gologs
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+)
+
+// SetCompressRotated 开启后按大小或按天轮转出的归档文件在后台 goroutine 中压缩为 归档名.gz, 完成后删除未压缩的归档.
+// 当前正在写入的日志文件不会被压缩. 压缩失败时保留原归档, 错误记录到 LastWriteError 并交给错误处理函数.
+// Close 会等待进行中的压缩完成
+func (log *Logger) SetCompressRotated(b bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.compressRotated = b
+}
+
+// compressArchive 将归档文件 name 压缩为 name.gz 并删除 name, 在独立的 goroutine 中运行
+func (log *Logger) compressArchive(name string) {
+	defer log.compressWG.Done()
+	if err := gzipFile(name); err != nil {
+		log.writeError(fmt.Errorf("compressing rotated logfile: %w", err))
+	}
+}
+
+// gzipFile 先写入临时文件再重命名为 name.gz, 中途失败不会留下不完整的 .gz
+func gzipFile(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	tmp := name + ".gz.tmp"
+	dst, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	_, err = io.Copy(zw, src)
+	if cerr := zw.Close(); err == nil {
+		err = cerr
+	}
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, name+".gz")
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	src.Close()
+	return os.Remove(name)
+}
@@ -0,0 +1,76 @@
+package gologs
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestLogger_SetCompressRotated(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+	log := NewLogger(Debug)
+	log.SetOutput(io.Discard)
+	log.SetClock(func() time.Time { return time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC) })
+	log.SetFile(filename)
+	log.SetIsLogToFile(true)
+	log.SetRotateSize(64)
+	log.SetCompressRotated(true)
+
+	for i := 0; i < 10; i++ {
+		log.Warnf("line %d", i)
+	}
+	log.Close()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	want := []string{"app.log", "app.log.20240506-070809.1.gz", "app.log.20240506-070809.gz"}
+	if fmt.Sprint(names) != fmt.Sprint(want) {
+		t.Fatalf("expected compressed archives %v, got %v", want, names)
+	}
+
+	f, err := os.Open(filepath.Join(dir, want[2]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(zr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "[Warn] line 0 \n[Warn] line 1 \n[Warn] line 2 \n[Warn] line 3 \n" {
+		t.Fatalf("unexpected archive content %q", data)
+	}
+	if active, _ := os.ReadFile(filename); string(active) != "[Warn] line 8 \n[Warn] line 9 \n" {
+		t.Fatalf("expected active file to stay uncompressed, got %q", active)
+	}
+}
+
+func TestLogger_CompressRotatedError(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "missing.log.1")
+	log := NewLogger(Debug)
+	log.SetErrorHandler(func(error) {})
+	log.compressWG.Add(1)
+	log.compressArchive(name)
+
+	if err := log.LastWriteError(); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected compression failure in LastWriteError, got %v", err)
+	}
+}
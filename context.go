@@ -0,0 +1,108 @@
+package gologs
+
+import "context"
+
+type contextFieldsKey struct{}
+
+// ContextWithFields 返回携带结构化字段的 ctx, 与 ctx 中已有字段合并, 同名字段以新值为准.
+//
+// Go 不鼓励 goroutine 局部存储, 因此每个逻辑流程 (例如 worker 处理的一个任务) 的基础字段
+// 随 context 传递: 在流程入口设置一次, 之后通过 LogCtx/LogCtxf 输出的日志都会带上这些字段.
+// 字段只存在于返回的 ctx 及其派生 ctx 中, 不会泄漏到其他流程
+func ContextWithFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	return context.WithValue(ctx, contextFieldsKey{}, mergeFields(contextFields(ctx), fieldsFromMap(fields)))
+}
+
+// ContextFields 返回 ctx 中携带的字段, 没有时返回 nil
+func ContextFields(ctx context.Context) map[string]interface{} {
+	fields := contextFields(ctx)
+	if fields == nil {
+		return nil
+	}
+	m := make(map[string]interface{}, len(fields))
+	for _, f := range fields {
+		m[f.Key] = f.Value
+	}
+	return m
+}
+
+func contextFields(ctx context.Context) []Field {
+	if ctx == nil {
+		return nil
+	}
+	fields, _ := ctx.Value(contextFieldsKey{}).([]Field)
+	return fields
+}
+
+// ContextExtractor 从 ctx 中提取一个字段, 例如 trace ID 或 request ID. value 为空时该字段被跳过
+type ContextExtractor func(ctx context.Context) (key, value string)
+
+// SetContextFields 设置 *Ctx 系列方法从 ctx 中提取字段的函数, 按顺序追加在 ContextWithFields 设置的字段之后,
+// 用于读取其他中间件存放在 ctx 中的 ID 而不必在每次调用时传递. 为 nil 时只输出 ContextWithFields 的字段
+func (log *Logger) SetContextFields(extractors []ContextExtractor) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.ctxExtractors = extractors
+}
+
+// ctxFields 返回 ctx 中通过 ContextWithFields 设置的字段与各个 ContextExtractor 提取的字段
+func (log *Logger) ctxFields(ctx context.Context) []Field {
+	fields := contextFields(ctx)
+	root := log.root()
+	root.mu.RLock()
+	extractors := root.ctxExtractors
+	root.mu.RUnlock()
+	if ctx == nil || len(extractors) == 0 {
+		return fields
+	}
+	var extra []Field
+	for _, extract := range extractors {
+		if extract == nil {
+			continue
+		}
+		if k, v := extract(ctx); v != "" {
+			extra = append(extra, Field{Key: k, Value: v})
+		}
+	}
+	if len(extra) == 0 {
+		return fields
+	}
+	return mergeFields(fields, extra)
+}
+
+// LogCtx 以 level 输出 s, 并附带 ctx 中通过 ContextWithFields 设置以及由 SetContextFields 提取的字段
+func (log *Logger) LogCtx(ctx context.Context, level LogLevel, s interface{}) {
+	log.logInterface(nil, level, log.ctxFields(ctx), s)
+}
+
+// LogCtxf 以 level 输出格式化消息, 附带的字段与 LogCtx 相同
+func (log *Logger) LogCtxf(ctx context.Context, level LogLevel, format string, s ...interface{}) {
+	log.logInterfacef(nil, level, log.ctxFields(ctx), format, s...)
+}
+
+func (log *Logger) DebugCtx(ctx context.Context, s interface{}) {
+	log.logInterface(nil, Debug, log.ctxFields(ctx), s)
+}
+
+func (log *Logger) InfoCtx(ctx context.Context, s interface{}) {
+	log.logInterface(nil, Info, log.ctxFields(ctx), s)
+}
+
+func (log *Logger) HintCtx(ctx context.Context, s interface{}) {
+	log.logInterface(nil, Hint, log.ctxFields(ctx), s)
+}
+
+func (log *Logger) ImportantCtx(ctx context.Context, s interface{}) {
+	log.logInterface(nil, Important, log.ctxFields(ctx), s)
+}
+
+func (log *Logger) WarnCtx(ctx context.Context, s interface{}) {
+	log.logInterface(nil, Warn, log.ctxFields(ctx), s)
+}
+
+func (log *Logger) ErrorCtx(ctx context.Context, s interface{}) {
+	if log.skipNil(s) {
+		return
+	}
+	log.logInterface(nil, Error, log.ctxFields(ctx), s)
+}
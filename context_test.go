@@ -0,0 +1,112 @@
+package gologs
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLogger_LogCtxFields(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+
+	ctx := ContextWithFields(context.Background(), map[string]interface{}{"worker": 1})
+	ctx = ContextWithFields(ctx, map[string]interface{}{"job": "a b"})
+	log.LogCtx(ctx, Warn, "start")
+	if got := buf.String(); got != "[Warn] start worker=1 job=\"a b\" \n" {
+		t.Fatalf("unexpected output: %q", got)
+	}
+
+	buf.Reset()
+	log.LogCtxf(context.Background(), Warn, "no %s", "fields")
+	if got := buf.String(); got != "[Warn] no fields \n" {
+		t.Fatalf("unexpected output: %q", got)
+	}
+}
+
+func TestLogger_LogCtxFieldsDoNotLeak(t *testing.T) {
+	buf := &syncBuffer{}
+	log := NewLogger(Debug)
+	log.SetOutput(buf)
+
+	base := ContextWithFields(context.Background(), map[string]interface{}{"app": "x"})
+	var wg sync.WaitGroup
+	for _, worker := range []string{"w1", "w2"} {
+		wg.Add(1)
+		go func(worker string) {
+			defer wg.Done()
+			ctx := ContextWithFields(base, map[string]interface{}{"worker": worker})
+			log.LogCtx(ctx, Warn, worker)
+		}(worker)
+	}
+	wg.Wait()
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		switch {
+		case strings.Contains(line, "[Warn] w1 "):
+			if !strings.Contains(line, "worker=w1") || strings.Contains(line, "worker=w2") {
+				t.Fatalf("fields leaked between flows: %q", line)
+			}
+		case strings.Contains(line, "[Warn] w2 "):
+			if !strings.Contains(line, "worker=w2") || strings.Contains(line, "worker=w1") {
+				t.Fatalf("fields leaked between flows: %q", line)
+			}
+		default:
+			t.Fatalf("unexpected line: %q", line)
+		}
+		if !strings.Contains(line, "app=x") {
+			t.Fatalf("expected shared base field: %q", line)
+		}
+	}
+
+	if got := ContextFields(base); len(got) != 1 || got["app"] != "x" {
+		t.Fatalf("base context was mutated: %v", got)
+	}
+}
+
+type traceIDKey struct{}
+
+func TestLogger_SetContextFields(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetContextFields([]ContextExtractor{
+		func(ctx context.Context) (string, string) {
+			id, _ := ctx.Value(traceIDKey{}).(string)
+			return "trace_id", id
+		},
+		nil,
+	})
+
+	ctx := context.WithValue(context.Background(), traceIDKey{}, "t-1")
+	ctx = ContextWithFields(ctx, map[string]interface{}{"user": "alice"})
+	log.WarnCtx(ctx, "checkout")
+	log.Named("db").ErrorCtx(ctx, "timeout")
+	log.InfoCtx(ctx, "paid")
+	want := "[Warn] checkout user=alice trace_id=t-1 \n[Error] timeout component=db user=alice trace_id=t-1 \n[-] paid user=alice trace_id=t-1 "
+	if got := buf.String(); !strings.HasPrefix(got, want) {
+		t.Fatalf("expected extracted fields, got %q", got)
+	}
+
+	buf.Reset()
+	log.WarnCtx(context.Background(), "missing")
+	if got := buf.String(); got != "[Warn] missing \n" {
+		t.Fatalf("expected missing values to be skipped, got %q", got)
+	}
+}
+
+func TestLogger_ErrorCtxSkipNilError(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetSkipNilError(true)
+
+	log.ErrorCtx(context.Background(), nil)
+	log.Named("db").ErrorCtx(context.Background(), nil)
+	if buf.Len() != 0 {
+		t.Fatalf("expected nil errors to be skipped on the root and child, got %q", buf.String())
+	}
+}
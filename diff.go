@@ -0,0 +1,119 @@
+package gologs
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+)
+
+// Diff 比较 before 与 after, 以 level 输出每个发生变化的字段 "field: before -> after", 每个变化一条日志,
+// label 作为 diff 字段附加在日志上. 嵌套的结构体、切片和 map 逐项比较, 未导出字段被忽略.
+// 两者相同时不输出任何内容
+func (log *Logger) Diff(level LogLevel, label string, before, after interface{}) {
+	if !log.root().levelEnabled(level) {
+		return
+	}
+	fields := []Field{{Key: "diff", Value: label}}
+	for _, change := range diffValues(reflect.ValueOf(before), reflect.ValueOf(after)) {
+		log.logInterface(nil, level, fields, change)
+	}
+}
+
+func diffValues(a, b reflect.Value) []string {
+	var changes []string
+	diffValue("", a, b, &changes)
+	return changes
+}
+
+func diffValue(path string, a, b reflect.Value, changes *[]string) {
+	if !a.IsValid() || !b.IsValid() {
+		if a.IsValid() != b.IsValid() {
+			addChange(path, a, b, changes)
+		}
+		return
+	}
+	if a.Type() != b.Type() {
+		addChange(path, a, b, changes)
+		return
+	}
+
+	switch a.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			if a.IsNil() != b.IsNil() {
+				addChange(path, a, b, changes)
+			}
+			return
+		}
+		diffValue(path, a.Elem(), b.Elem(), changes)
+	case reflect.Struct:
+		t := a.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			diffValue(joinPath(path, t.Field(i).Name), a.Field(i), b.Field(i), changes)
+		}
+	case reflect.Slice, reflect.Array:
+		if a.Len() != b.Len() {
+			addChange(path, a, b, changes)
+			return
+		}
+		for i := 0; i < a.Len(); i++ {
+			diffValue(path+"["+strconv.Itoa(i)+"]", a.Index(i), b.Index(i), changes)
+		}
+	case reflect.Map:
+		for _, k := range mapKeys(a, b) {
+			diffValue(path+"["+fmt.Sprint(k.Interface())+"]", a.MapIndex(k), b.MapIndex(k), changes)
+		}
+	default:
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			addChange(path, a, b, changes)
+		}
+	}
+}
+
+func addChange(path string, a, b reflect.Value, changes *[]string) {
+	change := diffText(a) + " -> " + diffText(b)
+	if path != "" {
+		change = path + ": " + change
+	}
+	*changes = append(*changes, change)
+}
+
+func diffText(v reflect.Value) string {
+	if !v.IsValid() {
+		return "<nil>"
+	}
+	if v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	return fieldValueText(v.Interface())
+}
+
+// joinPath 拼接字段路径, 顶层字段不带前导的点
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// mapKeys 返回两个 map 键的并集, 按文本排序保证输出顺序稳定
+func mapKeys(a, b reflect.Value) []reflect.Value {
+	seen := make(map[interface{}]bool)
+	var keys []reflect.Value
+	for _, m := range []reflect.Value{a, b} {
+		for _, k := range m.MapKeys() {
+			if !seen[k.Interface()] {
+				seen[k.Interface()] = true
+				keys = append(keys, k)
+			}
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return fmt.Sprint(keys[i].Interface()) < fmt.Sprint(keys[j].Interface())
+	})
+	return keys
+}
@@ -0,0 +1,55 @@
+package gologs
+
+import (
+	"bytes"
+	"testing"
+)
+
+type diffServer struct {
+	Host string
+	Port int
+}
+
+type diffConfig struct {
+	Name    string
+	Server  diffServer
+	Tags    []string
+	Limits  map[string]int
+	secret  string
+	Timeout *int
+}
+
+func TestLogger_Diff(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetFormatter(map[LogLevel]string{Info: "%s\n"})
+
+	timeout := 30
+	old := diffConfig{Name: "api", Server: diffServer{"localhost", 80}, Tags: []string{"a"},
+		Limits: map[string]int{"rps": 10}, secret: "x", Timeout: &timeout}
+	changed := old
+	changed.Server.Port = 8080
+	changed.secret = "y"
+
+	log.Diff(Info, "config", old, changed)
+	if got := buf.String(); got != "Server.Port: 80 -> 8080 diff=config\n" {
+		t.Fatalf("expected only the changed field, got %q", got)
+	}
+
+	buf.Reset()
+	changed = old
+	changed.Tags = []string{"b"}
+	changed.Limits = map[string]int{"rps": 10, "burst": 5}
+	log.Diff(Info, "config", old, changed)
+	want := "Tags[0]: a -> b diff=config\nLimits[burst]: <nil> -> 5 diff=config\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("expected nested changes, got %q", got)
+	}
+
+	buf.Reset()
+	log.Diff(Info, "config", old, old)
+	if buf.Len() != 0 {
+		t.Fatalf("expected no output for equal values, got %q", buf.String())
+	}
+}
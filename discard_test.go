@@ -0,0 +1,40 @@
+package gologs
+
+import "testing"
+
+// countingStringer 记录 String 被调用的次数, 用于确认被丢弃的日志没有格式化参数
+type countingStringer struct{ calls *int }
+
+func (c countingStringer) String() string {
+	*c.calls++
+	return "value"
+}
+
+func TestNewDiscardLogger(t *testing.T) {
+	log := NewDiscardLogger()
+	log.SetForceAll(true)
+	var emitted int
+	log.OnEmit(func(LogEntry) { emitted++ })
+
+	calls := 0
+	arg := countingStringer{&calls}
+	log.Error(arg)
+	log.Warnf("%s", arg)
+	log.Named("child").Infof("%v", arg)
+	log.LogAt(log.clockNow(), Error, arg)
+	if calls != 0 || emitted != 0 {
+		t.Fatalf("expected no formatting or output, got %d String calls and %d entries", calls, emitted)
+	}
+
+	if allocs := testing.AllocsPerRun(100, func() { log.Errorf("%s", "x") }); allocs != 0 {
+		t.Fatalf("expected a disabled call not to allocate, got %v allocs", allocs)
+	}
+}
+
+func BenchmarkDiscardLogger(b *testing.B) {
+	log := NewDiscardLogger()
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		log.Infof("request %d handled", i)
+	}
+}
@@ -0,0 +1,89 @@
+package gologs
+
+// Entry 是携带一组结构化字段的轻量日志句柄, 由 WithFields 创建. 它只保存 Logger 与字段,
+// 不修改原 Logger, 可以在每个请求中创建并在多个 goroutine 中使用
+type Entry struct {
+	log    *Logger
+	fields []Field
+}
+
+// WithFields 返回携带 fields 的 Entry, 通过它输出的日志都附带这些字段 (文本中以 key=value 追加, JSON 中为对象成员).
+// 与 With 不同, Entry 只提供输出日志的方法, 适合一次性的上下文, 如 log.WithFields(...).Info("done")
+func (log *Logger) WithFields(fields map[string]interface{}) *Entry {
+	return &Entry{log: log, fields: log.limitFields(fieldsFromMap(fields))}
+}
+
+// WithFields 返回在当前字段基础上追加 fields 的新 Entry, 同名字段以新值为准
+func (e *Entry) WithFields(fields map[string]interface{}) *Entry {
+	return &Entry{log: e.log, fields: e.log.limitFields(mergeFields(e.fields, fieldsFromMap(fields)))}
+}
+
+func (e *Entry) Log(level LogLevel, s interface{}) {
+	e.log.logInterface(nil, level, e.fields, s)
+}
+
+func (e *Entry) Logf(level LogLevel, format string, s ...interface{}) {
+	e.log.logInterfacef(nil, level, e.fields, format, s...)
+}
+
+func (e *Entry) Debug(s interface{}) {
+	e.log.logInterface(nil, Debug, e.fields, s)
+}
+
+func (e *Entry) Debugf(format string, s ...interface{}) {
+	e.log.logInterfacef(nil, Debug, e.fields, format, s...)
+}
+
+func (e *Entry) Info(s interface{}) {
+	e.log.logInterface(nil, Info, e.fields, s)
+}
+
+func (e *Entry) Infof(format string, s ...interface{}) {
+	e.log.logInterfacef(nil, Info, e.fields, format, s...)
+}
+
+func (e *Entry) Hint(s interface{}) {
+	e.log.logInterface(nil, Hint, e.fields, s)
+}
+
+func (e *Entry) Hintf(format string, s ...interface{}) {
+	e.log.logInterfacef(nil, Hint, e.fields, format, s...)
+}
+
+func (e *Entry) Important(s interface{}) {
+	e.log.logInterface(nil, Important, e.fields, s)
+}
+
+func (e *Entry) Importantf(format string, s ...interface{}) {
+	e.log.logInterfacef(nil, Important, e.fields, format, s...)
+}
+
+func (e *Entry) Warn(s interface{}) {
+	e.log.logInterface(nil, Warn, e.fields, s)
+}
+
+func (e *Entry) Warnf(format string, s ...interface{}) {
+	e.log.logInterfacef(nil, Warn, e.fields, format, s...)
+}
+
+func (e *Entry) Error(s interface{}) {
+	if e.log.skipNil(s) {
+		return
+	}
+	e.log.logInterface(nil, Error, e.fields, s)
+}
+
+func (e *Entry) Errorf(format string, s ...interface{}) {
+	e.log.logInterfacef(nil, Error, e.fields, format, s...)
+}
+
+// Fatal 以 Fatal 等级输出日志后退出进程, 见 Logger.Fatal
+func (e *Entry) Fatal(s interface{}) {
+	e.log.logInterface(nil, Fatal, e.fields, s)
+	e.log.exitFatal()
+}
+
+func (e *Entry) Fatalf(format string, s ...interface{}) {
+	e.log.logInterfacef(nil, Fatal, e.fields, format, s...)
+	e.log.exitFatal()
+}
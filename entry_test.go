@@ -0,0 +1,59 @@
+package gologs
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLogger_WithFields(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+
+	entry := log.WithFields(map[string]interface{}{"req_id": "r1", "user": "bob smith"})
+	entry.Warnf("done in %dms", 5)
+	if got := buf.String(); got != `[Warn] done in 5ms req_id=r1 user="bob smith" `+"\n" {
+		t.Fatalf("expected fields appended in text mode, got %q", got)
+	}
+
+	buf.Reset()
+	log.Warn("parent")
+	if got := buf.String(); got != "[Warn] parent \n" {
+		t.Fatalf("expected parent logger to be unchanged, got %q", got)
+	}
+
+	buf.Reset()
+	log.SetJSON(true)
+	log.SetClock(func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) })
+	entry.WithFields(map[string]interface{}{"user": "alice"}).Error("failed")
+	want := `{"time":"2024-01-01T00:00:00Z","level":"Error","message":"failed","req_id":"r1","user":"alice"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("expected fields as json members, got %q", got)
+	}
+}
+
+func TestLogger_WithFieldsConcurrent(t *testing.T) {
+	var buf syncBuffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			log.WithFields(map[string]interface{}{"req_id": i}).Warn("handled")
+		}(i)
+	}
+	wg.Wait()
+
+	for i := 0; i < 8; i++ {
+		if !strings.Contains(buf.String(), fmt.Sprintf("[Warn] handled req_id=%d \n", i)) {
+			t.Fatalf("missing entry for request %d in %q", i, buf.String())
+		}
+	}
+}
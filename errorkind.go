@@ -0,0 +1,46 @@
+package gologs
+
+import "errors"
+
+// errorKindKey 为日志中的错误匹配到 SetErrorMatchers 登记的哨兵错误时追加的字段
+const errorKindKey = "error_kind"
+
+// SetErrorMatchers 登记已知的哨兵错误. 日志参数 (Error(err)、Errorf 的参数) 或字段值中的 error 通过 errors.Is
+// 匹配到其中之一时, 追加 error_kind 字段, 值为该哨兵错误的 Error() 文本, 按登记顺序取第一个匹配.
+// 便于仪表盘按错误类型分组而不必解析消息. 不传参数时清除登记
+func (log *Logger) SetErrorMatchers(sentinels ...error) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.errorMatchers = sentinels
+}
+
+// errorKindFields 在 values 或 fields 中的 error 匹配到已登记的哨兵错误时, 返回追加了 error_kind 的字段.
+// 调用方需持有读锁
+func (log *Logger) errorKindFields(fields []Field, values ...interface{}) []Field {
+	if len(log.errorMatchers) == 0 {
+		return fields
+	}
+	kind := log.errorKind(values...)
+	for i := 0; kind == "" && i < len(fields); i++ {
+		kind = log.errorKind(fields[i].Value)
+	}
+	if kind == "" {
+		return fields
+	}
+	return mergeFields(fields, []Field{{Key: errorKindKey, Value: kind}})
+}
+
+func (log *Logger) errorKind(values ...interface{}) string {
+	for _, v := range values {
+		err, ok := v.(error)
+		if !ok {
+			continue
+		}
+		for _, sentinel := range log.errorMatchers {
+			if errors.Is(err, sentinel) {
+				return sentinel.Error()
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,50 @@
+package gologs
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"testing"
+)
+
+func TestLogger_SetErrorMatchers(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetJSON(true)
+	errTimeout := errors.New("timeout")
+	log.SetErrorMatchers(os.ErrNotExist, errTimeout)
+
+	kind := func() interface{} {
+		t.Helper()
+		var obj map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &obj); err != nil {
+			t.Fatal(err)
+		}
+		buf.Reset()
+		return obj[errorKindKey]
+	}
+
+	log.Error(fmt.Errorf("dial backend: %w", errTimeout))
+	if got := kind(); got != "timeout" {
+		t.Fatalf("expected error_kind for wrapped sentinel, got %v", got)
+	}
+
+	log.Errorf("open config: %v", &os.PathError{Op: "open", Path: "a.toml", Err: os.ErrNotExist})
+	if got := kind(); got != os.ErrNotExist.Error() {
+		t.Fatalf("expected error_kind from Errorf argument, got %v", got)
+	}
+
+	log.ErrorFields("read failed", map[string]interface{}{"err": fmt.Errorf("read: %w", errTimeout)})
+	if got := kind(); got != "timeout" {
+		t.Fatalf("expected error_kind from field value, got %v", got)
+	}
+
+	log.Error(io.EOF)
+	if got := kind(); got != nil {
+		t.Fatalf("expected no error_kind for unregistered errors, got %v", got)
+	}
+}
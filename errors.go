@@ -0,0 +1,142 @@
+package gologs
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrLogAfterClose 表示 Logger 在 Close 之后仍被调用. 此时日志调用不再输出, 该错误只交给错误处理函数一次,
+// 之后重新调用 InitLogFile (或 SetIsLogToFile(true)) 会恢复输出
+var ErrLogAfterClose = errors.New("gologs: log after close")
+
+// errorReportLimit 为每秒最多交给 errorHandler 的内部错误数量
+const errorReportLimit = 10
+
+func defaultErrorHandler(err error) {
+	fmt.Printf("Error: %s\n", err.Error())
+}
+
+// LoggerError 记录一次日志内部错误及其发生时间
+type LoggerError struct {
+	Err  error
+	Time time.Time
+}
+
+func (e *LoggerError) Error() string {
+	return e.Time.Format(time.RFC3339) + " " + e.Err.Error()
+}
+
+func (e *LoggerError) Unwrap() error {
+	return e.Err
+}
+
+// LastError 返回最近一次内部错误 (*LoggerError, 包含发生时间), 没有时返回 nil.
+// 被限流丢弃的错误同样会被记录, 可用于健康检查报告日志输出是否降级
+func (log *Logger) LastError() error {
+	root := log.root()
+	root.errMu.Lock()
+	defer root.errMu.Unlock()
+	if root.lastErr == nil {
+		return nil
+	}
+	return root.lastErr
+}
+
+// LastWriteError 返回最近一次写日志文件失败的错误, 没有时返回 nil. 与 LastError 不同,
+// 它只记录写文件错误, 可用于判断文件日志是否已经失效
+func (log *Logger) LastWriteError() error {
+	root := log.root()
+	root.errMu.Lock()
+	defer root.errMu.Unlock()
+	return root.lastWriteErr
+}
+
+// ClearLastError 清除 LastError 与 LastWriteError 记录的错误
+func (log *Logger) ClearLastError() {
+	root := log.root()
+	root.errMu.Lock()
+	defer root.errMu.Unlock()
+	root.lastErr = nil
+	root.lastWriteErr = nil
+}
+
+// SetErrorHandler 设置日志内部错误 (打开文件、写文件失败等) 的处理函数, 传入 nil 恢复默认的打印到标准输出.
+// handler 内部再次写日志产生的错误会被丢弃, 不会递归调用 handler
+func (log *Logger) SetErrorHandler(handler func(error)) {
+	log.errMu.Lock()
+	defer log.errMu.Unlock()
+	log.errorHandler = handler
+}
+
+// handleError 将内部错误交给 errorHandler.
+// 同一时刻只处理一个错误, 处理期间产生的嵌套错误 (包括其他 goroutine 上报的) 直接丢弃,
+// 并按 errorReportLimit 限流, 避免写失败时错误处理自身放大成错误风暴
+func (log *Logger) handleError(err error) {
+	log.errMu.Lock()
+	log.lastErr = &LoggerError{Err: err, Time: time.Now()}
+	handler := log.errorHandler
+	log.errMu.Unlock()
+
+	if !log.inErrorHandler.CompareAndSwap(false, true) {
+		return
+	}
+	defer log.inErrorHandler.Store(false)
+
+	if !log.allowErrorReport() {
+		return
+	}
+
+	if handler == nil {
+		handler = defaultErrorHandler
+	}
+	handler(err)
+}
+
+// deferError 记录内部错误, 推迟到 reportErrors 时再交给 errorHandler. 用于持有 mu 读锁的输出路径:
+// errorHandler 可能再次写日志而在同一 goroutine 上重新获取读锁, 若此时有 setter 在等待写锁会导致死锁
+func (log *Logger) deferError(err error) {
+	log.errMu.Lock()
+	log.lastErr = &LoggerError{Err: err, Time: time.Now()}
+	log.pendingErrs = append(log.pendingErrs, err)
+	log.errMu.Unlock()
+	log.hasPendingErrs.Store(true)
+}
+
+// reportErrors 将 deferError 推迟的错误依次交给 errorHandler, 调用方不能持有 mu
+func (log *Logger) reportErrors() {
+	if !log.hasPendingErrs.Load() {
+		return
+	}
+	log.errMu.Lock()
+	errs := log.pendingErrs
+	log.pendingErrs = nil
+	log.hasPendingErrs.Store(false)
+	log.errMu.Unlock()
+	for _, err := range errs {
+		log.handleError(err)
+	}
+}
+
+// reportLogAfterClose 在第一次于 Close 之后写日志时报告 ErrLogAfterClose, 调用方持有读锁, 因此推迟报告
+func (log *Logger) reportLogAfterClose() {
+	if log.closeWarned.CompareAndSwap(false, true) {
+		log.deferError(ErrLogAfterClose)
+	}
+}
+
+func (log *Logger) allowErrorReport() bool {
+	log.errMu.Lock()
+	defer log.errMu.Unlock()
+
+	now := time.Now()
+	if now.Sub(log.errWindow) >= time.Second {
+		log.errWindow = now
+		log.errCount = 0
+	}
+	if log.errCount >= errorReportLimit {
+		return false
+	}
+	log.errCount++
+	return true
+}
@@ -0,0 +1,186 @@
+package gologs
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// newBrokenFileLogger 返回一个日志文件已被关闭的 Logger, 每次写文件都会失败
+func newBrokenFileLogger(t *testing.T) *Logger {
+	log := NewLogger(Debug)
+	log.SetOutput(&bytes.Buffer{})
+	log.SetFile(filepath.Join(t.TempDir(), "broken.log"))
+	log.SetIsLogToFile(true)
+	log.logFile.Close()
+	return log
+}
+
+func TestLogger_ErrorHandlerRecursion(t *testing.T) {
+	log := newBrokenFileLogger(t)
+
+	calls := 0
+	log.SetErrorHandler(func(err error) {
+		calls++
+		// 常见错误用法: handler 自身写日志, 而写日志又会失败
+		log.Errorf("log write failed: %s", err)
+	})
+
+	log.Error("trigger")
+	if calls != 1 {
+		t.Fatalf("expected nested error reports to be dropped, handler called %d times", calls)
+	}
+}
+
+func TestLogger_ErrorHandlerRateLimit(t *testing.T) {
+	log := newBrokenFileLogger(t)
+
+	calls := 0
+	log.SetErrorHandler(func(err error) {
+		calls++
+	})
+
+	for i := 0; i < errorReportLimit*5; i++ {
+		log.Error("trigger")
+	}
+	if calls != errorReportLimit {
+		t.Fatalf("expected %d reported errors, got %d", errorReportLimit, calls)
+	}
+}
+
+func TestLogger_LastError(t *testing.T) {
+	log := newBrokenFileLogger(t)
+	log.SetErrorHandler(func(error) {})
+
+	if err := log.LastError(); err != nil {
+		t.Fatalf("expected no error before writing, got %v", err)
+	}
+
+	before := time.Now()
+	log.Error("trigger")
+
+	err := log.LastError()
+	var le *LoggerError
+	if !errors.As(err, &le) {
+		t.Fatalf("expected *LoggerError, got %v", err)
+	}
+	if !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("expected write error on closed file, got %v", err)
+	}
+	if le.Time.Before(before) {
+		t.Fatalf("unexpected error time %v", le.Time)
+	}
+
+	log.ClearLastError()
+	if err := log.LastError(); err != nil {
+		t.Fatalf("expected error to be cleared, got %v", err)
+	}
+}
+
+func TestLogger_SetIsLogToFileE(t *testing.T) {
+	log := NewLogger(Debug)
+	log.SetErrorHandler(func(error) {})
+	log.SetFile(filepath.Join(t.TempDir(), "missing", "app.log"))
+
+	err := log.SetIsLogToFileE(true)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected open error to be returned, got %v", err)
+	}
+	if err := log.InitLogFile(); err == nil {
+		t.Fatal("expected InitLogFile to return the open error")
+	}
+
+	log.SetFile(filepath.Join(t.TempDir(), "app.log"))
+	if err := log.SetIsLogToFileE(true); err != nil {
+		t.Fatalf("expected writable path to succeed, got %v", err)
+	}
+	log.Close()
+}
+
+func TestLogger_LastWriteError(t *testing.T) {
+	log := newBrokenFileLogger(t)
+	log.SetErrorHandler(func(error) {})
+
+	if err := log.LastWriteError(); err != nil {
+		t.Fatalf("expected no write error before writing, got %v", err)
+	}
+	log.Error("trigger")
+	if err := log.LastWriteError(); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("expected write error to be captured, got %v", err)
+	}
+
+	log.ClearLastError()
+	if err := log.LastWriteError(); err != nil {
+		t.Fatalf("expected write error to be cleared, got %v", err)
+	}
+}
+
+func TestLogger_LogAfterClose(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetFile(filepath.Join(t.TempDir(), "closed.log"))
+	log.SetIsLogToFile(true)
+	var reported []error
+	log.SetErrorHandler(func(err error) { reported = append(reported, err) })
+	log.Close()
+
+	log.Warn("after close")
+	log.Errorf("%s", "again")
+	log.Named("child").Info("child")
+	log.WithFields(map[string]interface{}{"k": 1}).Warn("entry")
+	if buf.Len() != 0 {
+		t.Fatalf("expected no output after Close, got %q", buf.String())
+	}
+	if len(reported) != 1 || !errors.Is(reported[0], ErrLogAfterClose) {
+		t.Fatalf("expected a single ErrLogAfterClose diagnostic, got %v", reported)
+	}
+
+	log.SetIsLogToFile(true)
+	log.Warn("reopened")
+	if buf.String() != "[Warn] reopened \n" || len(reported) != 1 {
+		t.Fatalf("expected output to resume after reopening, got %q (%v)", buf.String(), reported)
+	}
+}
+
+func TestLogger_ErrorHandlerLogsWithConcurrentSetter(t *testing.T) {
+	log := newBrokenFileLogger(t)
+	log.SetOutput(&syncBuffer{})
+	// handler 自身写日志, 若在持有读锁时调用, 会与等待写锁的 setter 死锁. 写日志前稍等, 让 setter 先开始等待写锁
+	log.SetErrorHandler(func(err error) {
+		time.Sleep(time.Millisecond)
+		log.Infof("log write failed: %v", err)
+	})
+
+	stop := make(chan struct{})
+	setterDone := make(chan struct{})
+	go func() {
+		defer close(setterDone)
+		for {
+			select {
+			case <-stop:
+				return
+			default:
+				log.SetLevel(Debug)
+			}
+		}
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			log.Error("trigger")
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("expected a logging error handler not to deadlock with a concurrent setter")
+	}
+	close(stop)
+	<-setterDone
+}
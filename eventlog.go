@@ -0,0 +1,43 @@
+package gologs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrEventLogUnsupported 在非 Windows 平台调用 SetEventLogOutput 时返回
+var ErrEventLogUnsupported = errors.New("gologs: windows event log is not supported on this platform")
+
+// eventLogWriter 由各平台实现, 负责把日志写入系统事件日志
+type eventLogWriter interface {
+	Report(level LogLevel, msg string) error
+	Close() error
+}
+
+// SetEventLogOutput 注册事件源 source, 之后的日志同时写入 Windows 事件日志.
+// Error 及以上映射为 Error, Warn 映射为 Warning, 其余为 Information.
+// 非 Windows 平台返回 ErrEventLogUnsupported
+func (log *Logger) SetEventLogOutput(source string) error {
+	w, err := openEventLog(source)
+	if err != nil {
+		return err
+	}
+
+	log.mu.Lock()
+	old := log.eventLog
+	log.eventLog = w
+	log.mu.Unlock()
+
+	if old != nil {
+		old.Close()
+	}
+	return nil
+}
+
+func (log *Logger) writeToEventLog(level LogLevel, line string) {
+	err := log.eventLog.Report(level, strings.TrimRight(line, "\n"))
+	if err != nil {
+		log.deferError(fmt.Errorf("writing to event log: %w", err))
+	}
+}
@@ -0,0 +1,7 @@
+//go:build !windows
+
+package gologs
+
+func openEventLog(source string) (eventLogWriter, error) {
+	return nil, ErrEventLogUnsupported
+}
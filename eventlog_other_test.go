@@ -0,0 +1,24 @@
+//go:build !windows
+
+package gologs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLogger_SetEventLogOutputUnsupported(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+
+	if err := log.SetEventLogOutput("gologs-test"); err != ErrEventLogUnsupported {
+		t.Fatalf("expected ErrEventLogUnsupported, got %v", err)
+	}
+
+	// 注册失败后正常输出不受影响
+	log.Warn("still works")
+	if buf.Len() == 0 {
+		t.Fatal("expected regular output after failed event log setup")
+	}
+}
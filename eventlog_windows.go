@@ -0,0 +1,79 @@
+//go:build windows
+
+package gologs
+
+import (
+	"syscall"
+	"unsafe"
+)
+
+var (
+	modadvapi32               = syscall.NewLazyDLL("advapi32.dll")
+	procRegisterEventSourceW  = modadvapi32.NewProc("RegisterEventSourceW")
+	procDeregisterEventSource = modadvapi32.NewProc("DeregisterEventSource")
+	procReportEventW          = modadvapi32.NewProc("ReportEventW")
+)
+
+// 事件类型, 见 ReportEventW 的 wType 参数
+const (
+	eventLogErrorType       = 0x0001
+	eventLogWarningType     = 0x0002
+	eventLogInformationType = 0x0004
+)
+
+type windowsEventLog struct {
+	handle uintptr
+}
+
+func openEventLog(source string) (eventLogWriter, error) {
+	src, err := syscall.UTF16PtrFromString(source)
+	if err != nil {
+		return nil, err
+	}
+	h, _, err := procRegisterEventSourceW.Call(0, uintptr(unsafe.Pointer(src)))
+	if h == 0 {
+		return nil, err
+	}
+	return &windowsEventLog{handle: h}, nil
+}
+
+func (w *windowsEventLog) Report(level LogLevel, msg string) error {
+	var etype uint16
+	switch {
+	case level >= Error:
+		etype = eventLogErrorType
+	case level >= Warn:
+		etype = eventLogWarningType
+	default:
+		etype = eventLogInformationType
+	}
+
+	str, err := syscall.UTF16PtrFromString(msg)
+	if err != nil {
+		return err
+	}
+	strs := []*uint16{str}
+	r, _, err := procReportEventW.Call(
+		w.handle,
+		uintptr(etype),
+		0, // category
+		1, // event id
+		0, // user sid
+		uintptr(len(strs)),
+		0, // raw data size
+		uintptr(unsafe.Pointer(&strs[0])),
+		0, // raw data
+	)
+	if r == 0 {
+		return err
+	}
+	return nil
+}
+
+func (w *windowsEventLog) Close() error {
+	r, _, err := procDeregisterEventSource.Call(w.handle)
+	if r == 0 {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package gologs
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// stubExit 替换 exit 并返回记录退出码的指针, -1 表示未退出
+func stubExit(t *testing.T) *int {
+	code := -1
+	exit = func(c int) { code = c }
+	t.Cleanup(func() { exit = os.Exit })
+	return &code
+}
+
+func TestLogger_Fatal(t *testing.T) {
+	code := stubExit(t)
+	filename := filepath.Join(t.TempDir(), "fatal.log")
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetFile(filename)
+	log.SetIsLogToFile(true)
+
+	log.Fatalf("cannot start: %s", "port in use")
+	if *code != 1 {
+		t.Fatalf("expected default exit code 1, got %d", *code)
+	}
+	if got := buf.String(); got != "[Fatal] cannot start: port in use \n" {
+		t.Fatalf("unexpected console output %q", got)
+	}
+	if log.logFile != nil {
+		t.Fatal("expected log file to be closed before exit")
+	}
+	if got, _ := os.ReadFile(filename); string(got) != "[Fatal] cannot start: port in use \n" {
+		t.Fatalf("expected last message in log file, got %q", got)
+	}
+}
+
+func TestLogger_SetFatalExitCode(t *testing.T) {
+	code := stubExit(t)
+	log := NewLogger(Error)
+	log.SetOutput(&bytes.Buffer{})
+	log.SetFatalExitCode(3)
+
+	log.Named("db").Fatal("gone")
+	if *code != 3 {
+		t.Fatalf("expected configured exit code 3, got %d", *code)
+	}
+}
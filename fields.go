@@ -0,0 +1,203 @@
+package gologs
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Field 是一个结构化字段, 以切片保存以保留添加顺序
+type Field struct {
+	Key   string
+	Value interface{}
+}
+
+// fieldsFromMap 将 map 转换为按键排序的字段, 保证输出顺序稳定
+func fieldsFromMap(m map[string]interface{}) []Field {
+	fields := make([]Field, 0, len(m))
+	for k, v := range m {
+		fields = append(fields, Field{Key: k, Value: v})
+	}
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Key < fields[j].Key })
+	return fields
+}
+
+// mergeFields 返回 base 与 extra 合并后的新切片, 同名字段以 extra 为准且保留原位置
+func mergeFields(base, extra []Field) []Field {
+	merged := make([]Field, len(base), len(base)+len(extra))
+	copy(merged, base)
+	for _, f := range extra {
+		replaced := false
+		for i := range merged {
+			if merged[i].Key == f.Key {
+				merged[i].Value = f.Value
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, f)
+		}
+	}
+	return merged
+}
+
+// fieldsTruncatedKey 为字段数量超过 SetMaxFields 上限时追加的标记字段
+const fieldsTruncatedKey = "fields_truncated"
+
+// SetMaxFields 限制每条日志携带的结构化字段数量, 超出时丢弃最早添加的字段并追加 fields_truncated=true.
+// With、Named 与 WithFields 累积字段时同样按该上限截断, 避免反复派生子 Logger 时字段无限增长.
+// n <= 0 表示不限制 (默认)
+func (log *Logger) SetMaxFields(n int) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.maxFields = n
+}
+
+// capFields 保留最新的 max 个字段, 截断时在末尾追加 fields_truncated=true.
+// fields 可能已经截断过 (子 Logger 保存的字段), 已有的标记字段不计入数量, 只在末尾保留一个
+func capFields(fields []Field, max int) []Field {
+	if max <= 0 || len(fields) <= max {
+		return fields
+	}
+	kept := make([]Field, 0, max+1)
+	truncated := false
+	for i := len(fields) - 1; i >= 0; i-- {
+		switch {
+		case fields[i].Key == fieldsTruncatedKey:
+			truncated = true
+		case len(kept) < max:
+			kept = append(kept, fields[i])
+		default:
+			truncated = true
+		}
+	}
+	for i, j := 0, len(kept)-1; i < j; i, j = i+1, j-1 {
+		kept[i], kept[j] = kept[j], kept[i]
+	}
+	if !truncated {
+		return fields
+	}
+	return append(kept, Field{Key: fieldsTruncatedKey, Value: true})
+}
+
+// limitFields 按根 Logger 的 SetMaxFields 上限截断子 Logger 与 Entry 保存的字段
+func (log *Logger) limitFields(fields []Field) []Field {
+	root := log.root()
+	root.mu.RLock()
+	max := root.maxFields
+	root.mu.RUnlock()
+	return capFields(fields, max)
+}
+
+// resolveFields 调用值为 func() interface{} 的延迟字段并返回替换后的副本, 没有延迟字段时原样返回.
+// 只在日志确定输出时调用, 保证每条日志只求值一次, 被过滤的日志不会求值
+func resolveFields(fields []Field) []Field {
+	for i, f := range fields {
+		if _, ok := f.Value.(func() interface{}); !ok {
+			continue
+		}
+		resolved := make([]Field, len(fields))
+		copy(resolved, fields)
+		for j := i; j < len(resolved); j++ {
+			if fn, ok := resolved[j].Value.(func() interface{}); ok {
+				resolved[j].Value = fn()
+			}
+		}
+		return resolved
+	}
+	return fields
+}
+
+// takeField 返回 key 对应字段的文本值以及去掉该字段后的切片
+func takeField(fields []Field, key string) (string, []Field) {
+	for i, f := range fields {
+		if f.Key == key {
+			rest := make([]Field, 0, len(fields)-1)
+			rest = append(rest, fields[:i]...)
+			return fmt.Sprint(f.Value), append(rest, fields[i+1:]...)
+		}
+	}
+	return "", fields
+}
+
+// SetTypedTextFields 开启后文本模式的字段值带有类型标记 (类似 InfluxDB 行协议): 整数追加 i 后缀 (count=5i),
+// 字符串总是加引号, 浮点数和布尔值原样输出, 便于解析程序从文本日志中还原类型
+func (log *Logger) SetTypedTextFields(typed bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.typedTextFields = typed
+}
+
+// fieldsText 将字段渲染为文本模式下追加在消息后的 " key=value" 形式
+func fieldsText(fields []Field, typed bool) string {
+	if len(fields) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	for _, f := range fields {
+		b.WriteByte(' ')
+		b.WriteString(f.Key)
+		b.WriteByte('=')
+		if typed {
+			b.WriteString(typedFieldValueText(f.Value))
+		} else {
+			b.WriteString(fieldValueText(f.Value))
+		}
+	}
+	return b.String()
+}
+
+func fieldValueText(v interface{}) string {
+	s := fmt.Sprint(v)
+	if s == "" || strings.ContainsAny(s, " =\"\t\n") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func typedFieldValueText(v interface{}) string {
+	switch v := v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v) + "i"
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return strconv.Quote(fmt.Sprint(v))
+	}
+}
+
+// LogFields 以 level 输出 msg 并附带 fields, 用于一次性的结构化日志, 不需要先创建 With 子 Logger.
+// 文本中字段以 key=value 追加, JSON 中合并为对象成员, 与 time、level 等内置键同名的字段被忽略
+func (log *Logger) LogFields(level LogLevel, msg string, fields map[string]interface{}) {
+	log.logInterface(nil, level, fieldsFromMap(fields), msg)
+}
+
+func (log *Logger) DebugFields(msg string, fields map[string]interface{}) {
+	log.logInterface(nil, Debug, fieldsFromMap(fields), msg)
+}
+
+func (log *Logger) InfoFields(msg string, fields map[string]interface{}) {
+	log.logInterface(nil, Info, fieldsFromMap(fields), msg)
+}
+
+func (log *Logger) HintFields(msg string, fields map[string]interface{}) {
+	log.logInterface(nil, Hint, fieldsFromMap(fields), msg)
+}
+
+func (log *Logger) ImportantFields(msg string, fields map[string]interface{}) {
+	log.logInterface(nil, Important, fieldsFromMap(fields), msg)
+}
+
+func (log *Logger) WarnFields(msg string, fields map[string]interface{}) {
+	log.logInterface(nil, Warn, fieldsFromMap(fields), msg)
+}
+
+func (log *Logger) ErrorFields(msg string, fields map[string]interface{}) {
+	log.logInterface(nil, Error, fieldsFromMap(fields), msg)
+}
@@ -0,0 +1,139 @@
+package gologs
+
+import (
+	"bytes"
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogger_DeferredFields(t *testing.T) {
+	var text, js bytes.Buffer
+	log := NewLogger(Warn)
+	log.SetOutput(&text)
+	log.AddSink(Sink{Writer: &js, Formatter: JSONFormatter{}})
+
+	calls := 0
+	ctx := ContextWithFields(context.Background(), map[string]interface{}{
+		"depth": func() interface{} {
+			calls++
+			return calls * 10
+		},
+	})
+
+	log.LogCtx(ctx, Debug, "filtered")
+	if calls != 0 {
+		t.Fatalf("deferred field resolved for filtered entry: %d calls", calls)
+	}
+
+	log.LogCtx(ctx, Warn, "emitted")
+	if calls != 1 {
+		t.Fatalf("expected exactly one resolution per entry, got %d", calls)
+	}
+	if !strings.Contains(text.String(), "depth=10") || !strings.Contains(js.String(), `"depth":10`) {
+		t.Fatalf("expected resolved value in all sinks, got %q and %q", text.String(), js.String())
+	}
+}
+
+func TestLogger_MaxFields(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetMaxFields(2)
+
+	ctx := context.Background()
+	for _, k := range []string{"a", "b", "c"} {
+		ctx = ContextWithFields(ctx, map[string]interface{}{k: 1})
+	}
+	log.LogCtx(ctx, Warn, "capped")
+	if got := buf.String(); got != "[Warn] capped b=1 c=1 fields_truncated=true \n" {
+		t.Fatalf("unexpected capped output: %q", got)
+	}
+
+	buf.Reset()
+	log.SetMaxFields(0)
+	log.LogCtx(ctx, Warn, "unlimited")
+	if got := buf.String(); got != "[Warn] unlimited a=1 b=1 c=1 \n" {
+		t.Fatalf("unexpected unlimited output: %q", got)
+	}
+}
+
+func TestLogger_TypedTextFields(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetTypedTextFields(true)
+	ctx := ContextWithFields(context.Background(), map[string]interface{}{
+		"count": 5,
+		"ok":    true,
+		"ratio": 0.5,
+		"user":  "bob",
+	})
+
+	log.LogCtx(ctx, Warn, "typed")
+	if got := buf.String(); got != `[Warn] typed count=5i ok=true ratio=0.5 user="bob" `+"\n" {
+		t.Fatalf("expected typed field values, got %q", got)
+	}
+
+	buf.Reset()
+	log.SetTypedTextFields(false)
+	log.LogCtx(ctx, Warn, "plain")
+	if got := buf.String(); got != "[Warn] plain count=5 ok=true ratio=0.5 user=bob \n" {
+		t.Fatalf("expected untyped field values, got %q", got)
+	}
+}
+
+func TestLogger_InfoFields(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SuffixFunc = func() string { return "" }
+
+	log.InfoFields("uploaded", map[string]interface{}{"bytes": 42, "file": "a.txt"})
+	if got := buf.String(); got != "[-] uploaded bytes=42 file=a.txt \n" {
+		t.Fatalf("expected fields appended in text mode, got %q", got)
+	}
+
+	buf.Reset()
+	log.SetJSON(true)
+	log.SetClock(func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) })
+	log.WarnFields("uploaded", map[string]interface{}{"bytes": 42, "level": "fake", "time": "never"})
+	want := `{"time":"2024-01-01T00:00:00Z","level":"Warn","message":"uploaded","bytes":42}` + "\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("expected fields merged without overwriting reserved keys, got %q", got)
+	}
+}
+
+func TestLogger_MaxFieldsBoundsWith(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetMaxFields(2)
+
+	child := log
+	entry := log.WithFields(nil)
+	for i := 0; i < 100; i++ {
+		key := "k" + strconv.Itoa(i)
+		child = child.With(map[string]interface{}{key: i})
+		entry = entry.WithFields(map[string]interface{}{key: i})
+	}
+	// 最多 2 个字段加 1 个截断标记
+	if len(child.fields) != 3 || len(entry.fields) != 3 {
+		t.Fatalf("expected accumulated fields to stay bounded, got %d / %d", len(child.fields), len(entry.fields))
+	}
+
+	child.Warn("child")
+	entry.Warn("entry")
+	want := "[Warn] child k98=98 k99=99 fields_truncated=true \n[Warn] entry k98=98 k99=99 fields_truncated=true \n"
+	if got := buf.String(); got != want {
+		t.Fatalf("expected the newest fields and one marker, got %q", got)
+	}
+
+	buf.Reset()
+	child.WarnFields("call", map[string]interface{}{"extra": 1})
+	if got := buf.String(); got != "[Warn] call k99=99 extra=1 fields_truncated=true \n" {
+		t.Fatalf("unexpected output with call fields: %q", got)
+	}
+}
@@ -0,0 +1,66 @@
+package gologs
+
+import "io"
+
+// 以下包级函数作用于全局的 Log, 用法与标准库 log 包类似, 例如 gologs.Infof("listening on %s", addr).
+// 并发安全性与对应的 Logger 方法相同. Debug、Info 等名称已被等级常量占用, 因此只提供格式化形式,
+// 不带格式的输出请使用 gologs.Log.Info 等方法. 日志函数直接进入核心入口, 开启 SetReportCaller 时调用位置正确
+
+// SetLevel 设置 Log 的等级门限
+func SetLevel(level LogLevel) {
+	Log.SetLevel(level)
+}
+
+// GetLevel 返回 Log 的等级门限
+func GetLevel() LogLevel {
+	return Log.GetLevel()
+}
+
+// SetOutput 设置 Log 的输出
+func SetOutput(w io.Writer) {
+	Log.SetOutput(w)
+}
+
+// SetColor 设置 Log 是否输出颜色
+func SetColor(c bool) {
+	Log.SetColor(c)
+}
+
+// SetQuiet 设置 Log 是否静默
+func SetQuiet(q bool) {
+	Log.SetQuiet(q)
+}
+
+func Logf(level LogLevel, format string, s ...interface{}) {
+	Log.logInterfacef(nil, level, nil, format, s...)
+}
+
+func Debugf(format string, s ...interface{}) {
+	Log.logInterfacef(nil, Debug, nil, format, s...)
+}
+
+func Infof(format string, s ...interface{}) {
+	Log.logInterfacef(nil, Info, nil, format, s...)
+}
+
+func Hintf(format string, s ...interface{}) {
+	Log.logInterfacef(nil, Hint, nil, format, s...)
+}
+
+func Importantf(format string, s ...interface{}) {
+	Log.logInterfacef(nil, Important, nil, format, s...)
+}
+
+func Warnf(format string, s ...interface{}) {
+	Log.logInterfacef(nil, Warn, nil, format, s...)
+}
+
+func Errorf(format string, s ...interface{}) {
+	Log.logInterfacef(nil, Error, nil, format, s...)
+}
+
+// Fatalf 以 Fatal 等级输出格式化日志后退出进程, 见 Logger.Fatal
+func Fatalf(format string, s ...interface{}) {
+	Log.logInterfacef(nil, Fatal, nil, format, s...)
+	Log.exitFatal()
+}
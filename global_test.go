@@ -0,0 +1,69 @@
+package gologs
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// useGlobalLog 在测试期间将全局 Log 替换为 log
+func useGlobalLog(t *testing.T, log *Logger) {
+	saved := Log
+	Log = log
+	t.Cleanup(func() { Log = saved })
+}
+
+func TestGlobalFunctions(t *testing.T) {
+	useGlobalLog(t, NewLogger(Warn))
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	SetLevel(Debug)
+	if GetLevel() != Debug {
+		t.Fatalf("expected SetLevel to apply to Log, got %d", GetLevel())
+	}
+
+	Debugf("d%d", 1)
+	Warnf("w%d", 2)
+	Errorf("e%d", 3)
+	Logf(Warn, "l%d", 4)
+	if got := buf.String(); got != "[Debug] d1 \n[Warn] w2 \n[Error] e3 \n[Warn] l4 \n" {
+		t.Fatalf("unexpected global output %q", got)
+	}
+
+	buf.Reset()
+	SetQuiet(true)
+	Errorf("quiet")
+	SetQuiet(false)
+	SetColor(true)
+	Warnf("colored")
+	if got := buf.String(); !strings.HasPrefix(got, "\033[") || strings.Contains(got, "quiet") {
+		t.Fatalf("expected quiet and color settings to apply, got %q", got)
+	}
+}
+
+func TestGlobalFunctionsCaller(t *testing.T) {
+	useGlobalLog(t, NewLogger(Debug))
+	var buf bytes.Buffer
+	SetOutput(&buf)
+	Log.SetFormatter(map[LogLevel]string{Warn: "({{caller}}) %s\n"})
+	Log.SetReportCaller(true)
+
+	line := currentLine() + 1
+	Warnf("here")
+	if want := fmt.Sprintf("(global_test.go:%d) here\n", line); buf.String() != want {
+		t.Fatalf("expected %q, got %q", want, buf.String())
+	}
+}
+
+func TestGlobalFatalf(t *testing.T) {
+	code := stubExit(t)
+	useGlobalLog(t, NewLogger(Debug))
+	var buf bytes.Buffer
+	SetOutput(&buf)
+
+	Fatalf("bye %d", 1)
+	if *code != 1 || buf.String() != "[Fatal] bye 1 \n" {
+		t.Fatalf("expected fatal output and exit, got %q (code %d)", buf.String(), *code)
+	}
+}
@@ -1,12 +1,16 @@
 package gologs
 
 import (
+	"errors"
 	"fmt"
 	"io"
+	"math"
 	"os"
+	"reflect"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -25,6 +29,7 @@ const (
 	Important LogLevel = 24
 	Warn      LogLevel = 30
 	Error     LogLevel = 40
+	Fatal     LogLevel = 50
 )
 
 var Levels = map[LogLevel]string{
@@ -34,6 +39,7 @@ var Levels = map[LogLevel]string{
 	Warn:      "Warn",
 	Hint:      "Hint",
 	Important: "Important",
+	Fatal:     "Fatal",
 }
 
 // 颜色部分
@@ -45,6 +51,7 @@ var DefaultColorMap = map[LogLevel]func(string) string{
 	Hint:      CyanBold,
 	Warn:      YellowBold,
 	Important: PurpleBold,
+	Fatal:     RedBold,
 }
 
 var DefaultFormatterMap = map[LogLevel]string{
@@ -54,6 +61,7 @@ var DefaultFormatterMap = map[LogLevel]string{
 	Info:      "[-] %s {{suffix}}\n",
 	Hint:      "[+] %s {{suffix}}\n",
 	Important: "[*] %s {{suffix}}\n",
+	Fatal:     "[Fatal] %s \n",
 }
 
 func (l LogLevel) Name() string {
@@ -64,6 +72,14 @@ func (l LogLevel) Name() string {
 	}
 }
 
+// levelName 返回 Logger 中登记的等级名称, 未登记时回退到 LogLevel.Name
+func (log *Logger) levelName(level LogLevel) string {
+	if name, ok := log.levels[level]; ok {
+		return name
+	}
+	return level.Name()
+}
+
 func (l LogLevel) Formatter() string {
 	if formatter, ok := DefaultFormatterMap[l]; ok {
 		return formatter
@@ -94,16 +110,15 @@ func AddLevel(level LogLevel, name string, opts ...interface{}) {
 
 func NewLogger(level LogLevel) *Logger {
 	log := &Logger{
-		Level:     level,
-		Color:     false,
-		LogToFile: false,
-		writer:    os.Stdout,
-		levels:    Levels,
-		formatter: DefaultFormatterMap,
-		colorMap:  DefaultColorMap,
-		SuffixFunc: func() string {
-			return ", " + getCurtime()
-		},
+		Level:      level,
+		Color:      false,
+		LogToFile:  false,
+		writer:     os.Stdout,
+		levels:     Levels,
+		formatter:  DefaultFormatterMap,
+		colorMap:   DefaultColorMap,
+		SuffixFunc: defaultSuffix,
+		exitCode:   1,
 		PrefixFunc: func() string {
 			return ""
 		},
@@ -112,25 +127,55 @@ func NewLogger(level LogLevel) *Logger {
 	return log
 }
 
-// NewFileLogger create a pure file logger
-func NewFileLogger(filename string) (*Logger, error) {
-	file, err := os.OpenFile(filename, os.O_RDWR|os.O_CREATE, 0666)
-	if err != nil {
-		return nil, err
+// NewDiscardLogger 返回丢弃所有日志的 Logger, 用于测试和基准测试. 日志调用在加锁、格式化之前直接返回,
+// 几乎没有开销; writer 为 io.Discard, Level 高于所有等级, 之后的配置调用 (包括 SetForceAll) 都不会使其输出
+func NewDiscardLogger() *Logger {
+	log := NewLogger(LogLevel(math.MaxInt))
+	log.writer = io.Discard
+	log.discard = true
+	return log
+}
+
+// Option 用于在构造 Logger 时调整默认配置
+type Option func(*Logger)
+
+// WithRemoveOnClose 设置 Close 未显式传参时是否删除日志文件, 默认为 false
+func WithRemoveOnClose(remove bool) Option {
+	return func(log *Logger) {
+		log.removeOnClose = remove
 	}
+}
 
+// WithFileTruncate 设置打开日志文件时是否清空已有内容, 默认追加
+func WithFileTruncate(truncate bool) Option {
+	return func(log *Logger) {
+		log.fileTruncate = truncate
+	}
+}
+
+// NewFileLogger create a pure file logger
+func NewFileLogger(filename string, opts ...Option) (*Logger, error) {
 	log := &Logger{
-		Level:     Warn,
-		writer:    file,
-		formatter: DefaultFormatterMap,
-		levels:    Levels,
-		SuffixFunc: func() string {
-			return ", " + getCurtime()
-		},
+		Level:       Warn,
+		LogFileName: filename,
+		formatter:   DefaultFormatterMap,
+		levels:      Levels,
+		SuffixFunc:  defaultSuffix,
+		exitCode:    1,
 		PrefixFunc: func() string {
 			return ""
 		},
 	}
+	for _, opt := range opts {
+		opt(log)
+	}
+
+	file, err := os.OpenFile(filename, log.fileFlags(), 0666)
+	if err != nil {
+		return nil, err
+	}
+	log.logFile = file
+	log.writer = file
 	return log, nil
 }
 
@@ -145,204 +190,810 @@ type Logger struct {
 	SuffixFunc  func() string
 	PrefixFunc  func() string
 
+	discard   bool // NewDiscardLogger 创建, 构造后不再修改, 无需加锁读取
 	mu        sync.RWMutex
 	muf       sync.Mutex
 	logFile   *os.File
 	writer    io.Writer
+	outputs   []addedOutput
+	outputMu  sync.Mutex
 	levels    map[LogLevel]string
 	formatter map[LogLevel]string
 	colorMap  map[LogLevel]func(string) string
+	// levelToggles 记录 EnableLevel/DisableLevel 显式设置的等级, 优先于 Level 门限
+	levelToggles map[LogLevel]bool
+	levelFilter  func(LogLevel) bool
+	// errorMatchers 为 SetErrorMatchers 登记的哨兵错误
+	errorMatchers  []error
+	colorAuto      bool
+	colorForcePipe bool
+	// resetSeq 替换颜色函数结尾的 "\033[0m", 只在 hasResetSeq 为 true 时生效, 见 SetColorReset
+	resetSeq    string
+	hasResetSeq bool
+
+	contentColors []contentColorRule
+
+	removeOnClose    bool
+	consoleLevel     LogLevel
+	lineBuffered     bool
+	exitCode         int
+	pidFile          string
+	fileTruncate     bool
+	truncateMax      int64
+	truncateKeep     int64
+	rotateSize       int64
+	rotateDaily      bool
+	compressRotated  bool
+	syncOnWrite      bool
+	compressWG       sync.WaitGroup
+	reopenOnDelete   bool
+	reopenCheckedAt  time.Time // 最近一次检查日志文件路径的时间, 在 muf 保护下更新
+	fileDay          string    // 最近一次写入日志文件的日期, 在 muf 保护下更新
+	fileSize         int64     // 当前日志文件大小, 在 muf 保护下随写入累加
+	fileClosed       bool      // Close 之后不再在写入时自动打开日志文件
+	closed           bool      // Close 之后日志调用不再输出, 见 ErrLogAfterClose
+	closeWarned      atomic.Bool
+	json             bool
+	jsonIndent       bool
+	jsonSortKeys     bool
+	jsonTimeFormat   string
+	timeFormat       string
+	messageKey       string
+	successField     string
+	successThreshold LogLevel
+	jsonEpoch        EpochUnit
+	nilText          string
+	skipNilError     bool
+	errorOutput      io.Writer
+	errorThreshold   LogLevel
+	hostname         string
+	pid              string
+	maxFields        int
+	lokiLabels       []string
+	typedTextFields  bool
+	severityMapper   SeverityMapper
+	reportCaller     bool
+	forceAll         bool
+	sanitizeControl  bool
+	escapeNewlines   bool
+	utf8Policy       InvalidUTF8Policy
+	preWriteHook     func(level LogLevel, line string) (string, bool)
+
+	mmapFile      *mmapFile
+	eventLog      eventLogWriter
+	extSinks      []Sink
+	onEmit        []func(LogEntry)
+	hooks         []func(level LogLevel, msg string)
+	ctxExtractors []ContextExtractor
+	errRing       *entryRing
+	bufferPolicy  BufferPolicy
+
+	errorHandler   func(error)
+	inErrorHandler atomic.Bool
+	errMu          sync.Mutex
+	errWindow      time.Time
+	errCount       int
+	lastErr        *LoggerError
+	lastWriteErr   error
+	pendingErrs    []error
+	hasPendingErrs atomic.Bool
+
+	budgetMu       sync.Mutex
+	maxTotalBytes  int64
+	totalBytes     int64
+	budgetDropped  int64
+	budgetExceeded bool
+
+	rates rateCounter
+
+	countMu        sync.Mutex
+	counts         map[LogLevel]int
+	summaryOnClose bool
+
+	clock    func() time.Time
+	location *time.Location
+
+	idGenerator func() string
+
+	everyMu sync.Mutex
+	everyAt map[string]time.Time
+
+	stackLevel LogLevel
+	stackMu    sync.Mutex
+	stackSeen  map[string]bool
+	stackOrder []string
+
+	warmupLevel      LogLevel
+	warmupUntil      time.Time
+	warmupSuppressed atomic.Int64
+
+	// 子 Logger 指向根 Logger, 根 Logger 为 nil
+	parent *Logger
+	fields []Field
+	name   string
+
+	asyncMu        sync.RWMutex
+	async          *asyncWriter
+	asyncDrop      atomic.Bool
+	asyncDropped   atomic.Int64
+	drainTimeout   time.Duration
+	syncForTesting atomic.Bool
+
+	otlpMu sync.RWMutex
+	otlp   *otlpExporter
+
+	hbMu   sync.Mutex
+	hbStop chan struct{}
+	hbDone chan struct{}
 }
 
 func (log *Logger) SetQuiet(q bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
 	log.Quiet = q
 }
 
 func (log *Logger) SetClean(c bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
 	log.Clean = c
 }
 
+// SetRemoveOnClose 设置 Close 未显式传参时是否删除日志文件
+func (log *Logger) SetRemoveOnClose(remove bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.removeOnClose = remove
+}
+
+// SetNilText 设置 nil 参数渲染成的文本, 默认为空字符串
+func (log *Logger) SetNilText(text string) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.nilText = text
+}
+
+// SetSkipNilError 开启后 Error(nil) 不再输出, 记录 nil error 通常是调用方的 bug
+func (log *Logger) SetSkipNilError(skip bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.skipNilError = skip
+}
+
+// skipNil 判断 s 是否为按 SetSkipNilError 不输出的 nil error, 子 Logger 使用根 Logger 的设置
+func (log *Logger) skipNil(s interface{}) bool {
+	if s != nil {
+		return false
+	}
+	root := log.root()
+	root.mu.RLock()
+	defer root.mu.RUnlock()
+	return root.skipNilError
+}
+
+// SetColor 强制开启或关闭颜色. 开启后即使 writer 不是终端 (例如通过管道输出给 less -R) 也保持颜色.
+// 调用后取消 SetColorAuto 的自动检测
 func (log *Logger) SetColor(c bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.colorAuto = false
+	log.colorForcePipe = false
 	log.Color = c
 }
+
+// SetColorForcePipe 为 true 时所有输出都带颜色, 包括管道、重定向等非终端 writer 以及 AddOutput 添加的输出,
+// 用于有意通过管道交给 less -R 等能显示颜色的分页器的场景, 与 SetColorAuto 的自动检测相反.
+// false 时恢复 SetColorAuto 的自动检测
+func (log *Logger) SetColorForcePipe(b bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.colorForcePipe = b
+	log.colorAuto = !b
+	log.Color = b || isTerminal(log.writer)
+}
+
+// SetColorAuto 根据 writer 是否为终端自动决定是否开启颜色: 只有 *os.File 且为字符设备 (TTY) 时开启,
+// 重定向到文件、管道或其他 io.Writer 时关闭. 之后调用 SetOutput 会重新检测, 调用 SetColor 则恢复为强制模式
+func (log *Logger) SetColorAuto() {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.colorAuto = true
+	log.colorForcePipe = false
+	log.Color = isTerminal(log.writer)
+}
 func (log *Logger) SetIsLogToFile(l bool) {
-	log.LogToFile = l
-	if log.LogToFile {
-		log.InitLogFile()
-	} else {
-		// 关闭原日志文件
-		if log.logFile != nil {
-			log.logFile.Close()
-			log.logFile = nil
-		}
+	log.SetIsLogToFileE(l)
+}
+
+// SetIsLogToFileE 与 SetIsLogToFile 相同, 但返回打开日志文件的错误, 便于调用方发现日志文件不可写
+func (log *Logger) SetIsLogToFileE(l bool) error {
+	if l {
+		log.mu.Lock()
+		log.LogToFile = true
+		log.mu.Unlock()
+		return log.InitLogFile()
 	}
+	log.mu.Lock()
+	log.muf.Lock()
+	log.LogToFile = false
+	// 关闭原日志文件
+	if log.logFile != nil {
+		log.logFile.Close()
+		log.logFile = nil
+	}
+	log.muf.Unlock()
+	log.mu.Unlock()
+	return nil
 }
 
-func (log *Logger) InitLogFile() {
+// SetFileTruncate 设置 InitLogFile 打开日志文件时是否清空已有内容, 默认追加
+func (log *Logger) SetFileTruncate(truncate bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.fileTruncate = truncate
+}
+
+// fileFlags 返回打开日志文件的标志, InitLogFile 与 NewFileLogger 共用
+func (log *Logger) fileFlags() int {
+	if log.fileTruncate {
+		return os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+	}
+	return os.O_CREATE | os.O_WRONLY | os.O_APPEND
+}
+
+// InitLogFile 打开 LogFileName 作为日志文件, 失败时除交给错误处理函数外同时返回该错误
+func (log *Logger) InitLogFile() error {
+	// 先取 mu 再取 muf, 与输出路径的加锁顺序一致; 错误处理可能再次写日志, 必须在释放两把锁之后进行
+	log.mu.Lock()
+	log.muf.Lock()
 	// 关闭原日志文件
 	if log.logFile != nil {
 		log.logFile.Close()
 		log.logFile = nil
 	}
 
-	file, err := os.OpenFile(log.LogFileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	log.fileClosed = false
+	log.closed = false
+	log.closeWarned.Store(false)
+	err := log.openLogFile()
+	log.muf.Unlock()
+	log.mu.Unlock()
+
 	if err != nil {
-		fmt.Println("Error: Set log file is error")
+		log.handleError(err)
+		return err
+	}
+	return nil
+}
+
+// openLogFile 打开 LogFileName 并记录当前大小
+func (log *Logger) openLogFile() error {
+	log.fileSize = 0
+	file, err := os.OpenFile(log.LogFileName, log.fileFlags(), 0666)
+	if err != nil {
+		return fmt.Errorf("set log file: %w", err)
 	}
 	log.logFile = file
+	if info, err := file.Stat(); err == nil {
+		log.fileSize = info.Size()
+	}
+	return nil
 }
 
 func (log *Logger) SetColorMap(cm map[LogLevel]func(string) string) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.colorMap = cm
+}
+
+// OverrideColor 只修改 level 的颜色, 其他等级保持不变. colorMap 可能与 DefaultColorMap 共享, 因此复制后再修改
+func (log *Logger) OverrideColor(level LogLevel, fn func(string) string) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	cm := make(map[LogLevel]func(string) string, len(log.colorMap)+1)
+	for l, c := range log.colorMap {
+		cm[l] = c
+	}
+	cm[level] = fn
 	log.colorMap = cm
 }
 
+// SetForceAll 开启后忽略 Level 与 Quiet, 输出所有等级的日志.
+// 仅供开发调试临时使用, 不要在生产环境开启
+func (log *Logger) SetForceAll(b bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.forceAll = b
+}
+
 func (log *Logger) SetLevel(l LogLevel) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
 	log.Level = l
 }
 
+// GetLevel 返回当前的等级门限
+func (log *Logger) GetLevel() LogLevel {
+	log.mu.RLock()
+	defer log.mu.RUnlock()
+	return log.Level
+}
+
+// SetErrorsToStderr 开启后 Error 及以上等级 (或 SetErrorThreshold 设置的等级) 写到 os.Stderr,
+// 其余仍写到 SetOutput 设置的 writer. 等价于 SetErrorOutput(os.Stderr), 关闭时等价于 SetErrorOutput(nil)
+func (log *Logger) SetErrorsToStderr(b bool) {
+	if b {
+		log.SetErrorOutput(os.Stderr)
+	} else {
+		log.SetErrorOutput(nil)
+	}
+}
+
+// SetErrorOutput 设置错误输出: 等级达到 SetErrorThreshold 门限 (默认 Error) 的日志写到 w 而不是 SetOutput 设置的 writer.
+// 只影响默认输出, 日志文件与 sink 照常写入, F* 系列显式指定 writer 的方法也不受影响. 为 nil 时全部写到 writer (默认)
+func (log *Logger) SetErrorOutput(w io.Writer) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.errorOutput = w
+}
+
+// SetErrorThreshold 设置写到错误输出的最低等级, 例如设为 Warn 使警告也写到 stderr. level <= 0 时恢复默认的 Error
+func (log *Logger) SetErrorThreshold(level LogLevel) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.errorThreshold = level
+}
+
+// defaultWriter 返回 level 的日志在默认输出下写到的 writer
+func (log *Logger) defaultWriter(level LogLevel) io.Writer {
+	if log.errorOutput == nil {
+		return log.writer
+	}
+	threshold := log.errorThreshold
+	if threshold <= 0 {
+		threshold = Error
+	}
+	if level >= threshold {
+		return log.errorOutput
+	}
+	return log.writer
+}
+
+// SetReportHostname 开启后解析一次主机名并缓存, 文本模板中的 {{host}} 替换为主机名, JSON 输出增加 host 字段.
+// 获取主机名失败时使用 "unknown"
+func (log *Logger) SetReportHostname(b bool) {
+	host := ""
+	if b {
+		var err error
+		if host, err = os.Hostname(); err != nil || host == "" {
+			host = "unknown"
+		}
+	}
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.hostname = host
+}
+
+// SetReportPID 开启后文本模板中的 {{pid}} 替换为当前进程 PID, JSON 输出增加 pid 字段,
+// 便于区分多个进程写入同一个日志文件的内容. PID 在进程内不变, 开启时获取一次并缓存
+func (log *Logger) SetReportPID(b bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	if !b {
+		log.pid = ""
+		return
+	}
+	log.pid = strconv.Itoa(os.Getpid())
+}
+
+// SetPreWriteHook 设置写入前的钩子, 可改写渲染好的日志行 (脱敏、补充信息) 或返回 write=false 丢弃该日志.
+// 钩子作用于所有 sink: 先处理按 Logger 格式渲染的行, 返回 false 时整条日志丢弃;
+// 使用独立 Formatter 的 sink 渲染出的行同样经过钩子, 返回 false 时只跳过该 sink
+func (log *Logger) SetPreWriteHook(hook func(level LogLevel, line string) (modified string, write bool)) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.preWriteHook = hook
+}
+
 func (log *Logger) SetOutput(w io.Writer) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
 	log.writer = w
+	if log.colorAuto {
+		log.Color = isTerminal(w)
+	}
 }
 
 func (log *Logger) SetFile(filename string) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
 	log.LogFileName = filename
 }
 
 func (log *Logger) SetFormatter(formatter map[LogLevel]string) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
 	log.formatter = formatter
 }
 
+// consoleImpliedLevel 是 Console 系列输出在 SetConsoleLevel 门限判断时视为的等级
+const consoleImpliedLevel = Info
+
+// SetConsoleLevel 设置 Console、Consolef、FConsolef 的等级门限: Console 输出视为 Info 等级,
+// level 高于 Info 时 (如生产环境设为 Warn) 这些装饰性输出也被屏蔽. level <= 0 表示不限制 (默认), 只受 Clean 控制
+func (log *Logger) SetConsoleLevel(level LogLevel) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.consoleLevel = level
+}
+
+// consoleEnabled 判断 Console 系列方法是否输出, 调用方需持有 mu
+func (log *Logger) consoleEnabled() bool {
+	return !log.Clean && (log.consoleLevel <= 0 || consoleImpliedLevel >= log.consoleLevel)
+}
+
 func (log *Logger) Console(s string) {
-	if !log.Clean {
-		fmt.Fprint(log.writer, s)
+	root := log.root()
+	root.mu.RLock()
+	defer root.mu.RUnlock()
+	if root.consoleEnabled() {
+		writeLine(root.writer, s, root.lineBuffered)
 	}
 }
 
 func (log *Logger) Consolef(format string, s ...interface{}) {
-	if !log.Clean {
-		fmt.Fprintf(log.writer, format, s...)
+	root := log.root()
+	root.mu.RLock()
+	defer root.mu.RUnlock()
+	if root.consoleEnabled() {
+		writeLine(root.writer, fmt.Sprintf(format, s...), root.lineBuffered)
 	}
 }
 
 func (log *Logger) FConsolef(writer io.Writer, format string, s ...interface{}) {
-	if !log.Clean {
-		fmt.Fprintf(writer, format, s...)
+	root := log.root()
+	root.mu.RLock()
+	defer root.mu.RUnlock()
+	if root.consoleEnabled() {
+		writeLine(writer, fmt.Sprintf(format, s...), root.lineBuffered)
 	}
 }
 
-func (log *Logger) logInterface(writer io.Writer, level LogLevel, s interface{}) {
+// logInterface 与 logInterfacef 是日志输出的核心入口. 子 Logger (见 Named) 使用根 Logger 的配置与输出,
+// 只附加自身携带的字段
+// 输出完成后释放锁再通知 OnEmit 回调, 回调中可以安全地调用 Logger 的方法
+func (log *Logger) logInterface(writer io.Writer, level LogLevel, fields []Field, s interface{}) {
+	root := log.root()
+	if root.discard {
+		return
+	}
+	root.mu.RLock()
+	if !root.enabled(level) {
+		root.mu.RUnlock()
+		root.reportErrors()
+		return
+	}
+	e := root.newEntry(root.now(), level, root.errorKindFields(log.withFields(fields), s), root.message(s))
+	emitted := root.output(writer, e)
+	root.mu.RUnlock()
+	root.reportErrors()
+	if emitted {
+		root.notifyEmit(e)
+	}
+}
+
+func (log *Logger) logInterfacef(writer io.Writer, level LogLevel, fields []Field, format string, s ...interface{}) {
+	root := log.root()
+	if root.discard {
+		return
+	}
+	root.mu.RLock()
+	if !root.enabled(level) {
+		root.mu.RUnlock()
+		root.reportErrors()
+		return
+	}
+	e := root.newEntry(root.now(), level, root.errorKindFields(log.withFields(fields), s...), fmt.Sprintf(format, s...))
+	emitted := root.output(writer, e)
+	root.mu.RUnlock()
+	root.reportErrors()
+	if emitted {
+		root.notifyEmit(e)
+	}
+}
+
+// levelEnabled 判断 level 的日志是否会通过过滤, 与 enabled 不同, 它自行加锁且没有副作用 (不计入预热期丢弃数、
+// 不报告 ErrLogAfterClose), 供需要在逐条输出前跳过昂贵准备工作的方法使用
+func (log *Logger) levelEnabled(level LogLevel) bool {
+	if log.discard {
+		return false
+	}
 	log.mu.RLock()
 	defer log.mu.RUnlock()
-	if !log.Quiet && level >= log.Level {
-		line := log.Format(level, s)
-		if log.Color {
-			fmt.Fprint(writer, log.SetLevelColor(level, line))
-		} else {
-			fmt.Fprint(writer, line)
+	if log.closed {
+		return false
+	}
+	return log.forceAll || !log.Quiet && log.levelAllowed(level)
+}
+
+// enabled 判断 level 的日志是否需要输出, 调用方需持有读锁
+func (log *Logger) enabled(level LogLevel) bool {
+	if log.closed {
+		log.reportLogAfterClose()
+		return false
+	}
+	if log.forceAll {
+		return true
+	}
+	return !log.Quiet && log.levelAllowed(level) && !log.inWarmup(level)
+}
+
+// message 将非格式化日志的参数渲染为消息文本
+func (log *Logger) message(s interface{}) string {
+	if s == nil {
+		return log.nilText
+	}
+	return fmt.Sprint(s)
+}
+
+func (log *Logger) newEntry(t time.Time, level LogLevel, fields []Field, msg string) *LogEntry {
+	return &LogEntry{
+		Time:    log.inLocation(t),
+		Level:   level,
+		Message: log.sanitizeMessage(msg),
+		Fields:  resolveFields(capFields(fields, log.maxFields)),
+	}
+}
+
+// render 按 Logger 当前的输出模式 (文本或 JSON) 渲染日志条目
+func (log *Logger) render(e *LogEntry) string {
+	if log.json {
+		return log.formatJSON(e)
+	}
+	return log.formatText(log.formatter, e)
+}
+
+// output 将日志条目写到各个 sink 以及已开启的事件日志等输出. writer 为 nil 表示使用 Logger 的默认输出.
+// 日志被钩子或输出预算丢弃时返回 false
+func (log *Logger) output(writer io.Writer, e *LogEntry) bool {
+	color := log.Color
+	fanout := writer == nil
+	if fanout {
+		writer = log.defaultWriter(e.Level)
+		if log.colorAuto && writer != log.writer {
+			color = isTerminal(writer)
 		}
+	} else if color && isRegularFile(writer) {
+		// F* 系列显式传入的日志文件不写入颜色转义码, 保持文件可 grep
+		color = false
+	}
+	// 调用链固定为 用户代码 -> 公开方法 -> logInterface/logInterfacef/logWith -> output
+	if log.reportCaller && e.Caller == nil {
+		e.Caller = callerAt(3)
+	}
+	if log.stackLevel > 0 && e.Level >= log.stackLevel {
+		e.Fields = mergeFields(e.Fields, log.stackFields(stackAt(3)))
+	}
 
-		// 写入到日志文件
-		if log.LogToFile {
-			log.writeToFile(line)
+	line := log.render(e)
+	if log.preWriteHook != nil {
+		var write bool
+		if line, write = log.preWriteHook(e.Level, line); !write {
+			return false
 		}
 	}
+	sinks := log.sinks(writer, color, fanout)
+	if !log.withinBudget(sinks, e, line) {
+		return false
+	}
+
+	log.countEntry(e.Level)
+	log.countRate(e.Level)
+	if log.errRing != nil && e.Level >= Error {
+		log.errRing.add(*e)
+	}
+
+	log.writeEntry(sinks, e, line)
+
+	// 写入到 Windows 事件日志
+	if log.eventLog != nil {
+		log.writeToEventLog(e.Level, stripHyperlinks(line))
+	}
+	log.exportOTLP(e)
+	return true
 }
 
-func (log *Logger) logInterfacef(writer io.Writer, level LogLevel, format string, s ...interface{}) {
-	log.mu.RLock()
-	defer log.mu.RUnlock()
-	if !log.Quiet && level >= log.Level {
-		line := log.Format(level, fmt.Sprintf(format, s...))
-		if log.Color {
-			fmt.Fprint(writer, log.SetLevelColor(level, line))
+// writeEntry 将日志条目写到 sinks, line 为按 Logger 当前格式渲染的结果
+func (log *Logger) writeEntry(sinks []Sink, e *LogEntry, line string) {
+	for _, sink := range sinks {
+		if e.Level < sink.MinLevel {
+			continue
+		}
+		if sink.Formatter == nil {
+			log.writeSink(sink.Writer, e, line, sink.Color && !log.json)
 		} else {
-			fmt.Fprint(writer, line)
+			_, isJSON := sink.Formatter.(JSONFormatter)
+			l := sink.Formatter.Format(log, e)
+			if log.preWriteHook != nil {
+				var write bool
+				if l, write = log.preWriteHook(e.Level, l); !write {
+					continue
+				}
+			}
+			log.writeSink(sink.Writer, e, l, sink.Color && !isJSON)
 		}
+	}
+}
 
-		// 写入到日志文件
-		if log.LogToFile {
-			log.writeToFile(line)
-		}
+// writeSink 写入一行日志. 颜色转义码会破坏 JSON, 调用方需保证 JSON 输出时 color 为 false
+func (log *Logger) writeSink(w io.Writer, e *LogEntry, line string, color bool) {
+	if color && e.color != nil {
+		log.write(w, log.colorReset(e.color(line)))
+	} else if c := log.contentColor(e.Message); color && c != nil {
+		log.write(w, log.colorReset(c(line)))
+	} else if color {
+		log.write(w, log.colorReset(log.SetLevelColor(e.Level, line)))
+	} else {
+		// 不带颜色的输出不支持终端超链接, 只保留链接文本
+		log.write(w, stripHyperlinks(line))
 	}
 }
 
 func (log *Logger) Log(level LogLevel, s interface{}) {
-	log.logInterface(log.writer, level, s)
+	log.logInterface(nil, level, nil, s)
 }
 
 func (log *Logger) Logf(level LogLevel, format string, s ...interface{}) {
-	log.logInterfacef(log.writer, level, format, s...)
+	log.logInterfacef(nil, level, nil, format, s...)
 }
 
 func (log *Logger) FLogf(writer io.Writer, level LogLevel, s ...interface{}) {
-	log.logInterface(writer, level, fmt.Sprintln(s...))
+	log.logWith(writer, time.Time{}, level, nil, func(*Logger) string { return fmt.Sprintln(s...) })
+}
+
+// LogAt 以指定的时间 t 代替当前时间输出日志, 用于回放历史事件或导入外部日志.
+// t 用于 JSON 的 time 字段以及默认 SuffixFunc 生成的时间
+func (log *Logger) LogAt(t time.Time, level LogLevel, s interface{}) {
+	log.logWith(nil, t, level, nil, func(root *Logger) string { return root.message(s) })
+}
+
+// LogColored 使用 color 代替 level 对应的颜色输出这一条日志, 不影响之后的调用. 未开启颜色时与 Log 相同
+func (log *Logger) LogColored(level LogLevel, color func(string) string, s interface{}) {
+	log.logWith(nil, time.Time{}, level, color, func(root *Logger) string { return root.message(s) })
+}
+
+// LogAtf 以指定的时间 t 输出格式化日志
+func (log *Logger) LogAtf(t time.Time, level LogLevel, format string, s ...interface{}) {
+	log.logWith(nil, t, level, nil, func(root *Logger) string { return fmt.Sprintf(format, s...) })
+}
+
+// logWith 供 LogAt、LogColored、FLogf 等需要指定时间、颜色或延迟渲染消息的方法使用, 与 logInterface 处于相同的调用深度.
+// writer 为 nil 时使用默认输出, t 为零值时使用当前时间, render 只在日志需要输出时调用
+func (log *Logger) logWith(writer io.Writer, t time.Time, level LogLevel, color func(string) string, render func(root *Logger) string) {
+	root := log.root()
+	if root.discard {
+		return
+	}
+	root.mu.RLock()
+	if !root.enabled(level) {
+		root.mu.RUnlock()
+		root.reportErrors()
+		return
+	}
+	if t.IsZero() {
+		t = root.now()
+	}
+	e := root.newEntry(t, level, log.fields, render(root))
+	e.color = color
+	emitted := root.output(writer, e)
+	root.mu.RUnlock()
+	root.reportErrors()
+	if emitted {
+		root.notifyEmit(e)
+	}
 }
 
 func (log *Logger) Important(s interface{}) {
-	log.logInterface(log.writer, Important, s)
+	log.logInterface(nil, Important, nil, s)
 }
 
 func (log *Logger) Importantf(format string, s ...interface{}) {
-	log.logInterfacef(log.writer, Important, format, s...)
+	log.logInterfacef(nil, Important, nil, format, s...)
 }
 
 func (log *Logger) FImportantf(writer io.Writer, format string, s ...interface{}) {
-	log.logInterfacef(writer, Important, format, s...)
+	log.logInterfacef(writer, Important, nil, format, s...)
 }
 
 func (log *Logger) Info(s interface{}) {
-	log.logInterface(log.writer, Info, s)
+	log.logInterface(nil, Info, nil, s)
 }
 
 func (log *Logger) Infof(format string, s ...interface{}) {
-	log.logInterfacef(log.writer, Info, format, s...)
+	log.logInterfacef(nil, Info, nil, format, s...)
 }
 
 func (log *Logger) Hint(s interface{}) {
-	log.logInterface(log.writer, Hint, s)
+	log.logInterface(nil, Hint, nil, s)
 }
 
 func (log *Logger) Hintf(format string, s ...interface{}) {
-	log.logInterfacef(log.writer, Hint, format, s...)
+	log.logInterfacef(nil, Hint, nil, format, s...)
 }
 func (log *Logger) FInfof(writer io.Writer, format string, s ...interface{}) {
-	log.logInterfacef(writer, Info, format, s...)
+	log.logInterfacef(writer, Info, nil, format, s...)
 }
 
 func (log *Logger) Error(s interface{}) {
-	log.logInterface(log.writer, Error, s)
+	if log.skipNil(s) {
+		return
+	}
+	log.logInterface(nil, Error, nil, s)
 }
 
 func (log *Logger) Errorf(format string, s ...interface{}) {
-	log.logInterfacef(log.writer, Error, format, s...)
+	log.logInterfacef(nil, Error, nil, format, s...)
+}
+
+// Fatal 以 Fatal 等级输出日志, 关闭日志文件保证内容落盘后以 SetFatalExitCode 设置的退出码 (默认 1) 退出进程
+func (log *Logger) Fatal(s interface{}) {
+	log.logInterface(nil, Fatal, nil, s)
+	log.exitFatal()
+}
+
+// Fatalf 以 Fatal 等级输出格式化日志后退出进程, 见 Fatal
+func (log *Logger) Fatalf(format string, s ...interface{}) {
+	log.logInterfacef(nil, Fatal, nil, format, s...)
+	log.exitFatal()
+}
+
+// SetFatalExitCode 设置 Fatal/Fatalf 退出进程时使用的退出码
+func (log *Logger) SetFatalExitCode(code int) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.exitCode = code
+}
+
+// exit 为 Fatal 退出进程的函数, 测试中替换以避免真正退出
+var exit = os.Exit
+
+func (log *Logger) exitFatal() {
+	root := log.root()
+	// 关闭日志文件, 保证最后一条日志写入磁盘; 进程即将退出, 不删除日志文件
+	root.mu.RLock()
+	code := root.exitCode
+	root.mu.RUnlock()
+	root.Close(false)
+	exit(code)
 }
 
 func (log *Logger) FErrorf(writer io.Writer, format string, s ...interface{}) {
-	log.logInterfacef(writer, Error, format, s...)
+	log.logInterfacef(writer, Error, nil, format, s...)
 }
 
 func (log *Logger) Warn(s interface{}) {
-	log.logInterface(log.writer, Warn, s)
+	log.logInterface(nil, Warn, nil, s)
 }
 
 func (log *Logger) Warnf(format string, s ...interface{}) {
-	log.logInterfacef(log.writer, Warn, format, s...)
+	log.logInterfacef(nil, Warn, nil, format, s...)
 }
 
 func (log *Logger) FWarnf(writer io.Writer, format string, s ...interface{}) {
-	log.logInterfacef(writer, Warn, format, s...)
+	log.logInterfacef(writer, Warn, nil, format, s...)
 }
 
 func (log *Logger) Debug(s interface{}) {
-	log.logInterface(log.writer, Debug, s)
+	log.logInterface(nil, Debug, nil, s)
 }
 
 func (log *Logger) Debugf(format string, s ...interface{}) {
-	log.logInterfacef(log.writer, Debug, format, s...)
+	log.logInterfacef(nil, Debug, nil, format, s...)
 }
 
 func (log *Logger) FDebugf(writer io.Writer, format string, s ...interface{}) {
-	log.logInterfacef(writer, Debug, format, s...)
+	log.logInterfacef(writer, Debug, nil, format, s...)
 }
 
 func (log *Logger) SetLevelColor(level LogLevel, line string) string {
@@ -356,59 +1007,222 @@ func (log *Logger) SetLevelColor(level LogLevel, line string) string {
 }
 
 func (log *Logger) Format(level LogLevel, s ...interface{}) string {
+	log.mu.RLock()
+	defer log.mu.RUnlock()
+	return log.formatWith(log.formatter, log.now(), level, s...)
+}
+
+// formatText 使用 templates 渲染文本格式的日志条目, 结构化字段以 key=value 追加在消息后.
+// 模板包含 {{component}} 时组件名填入该位置, 不再作为字段追加. {{caller}} 填入调用位置 (见 SetReportCaller).
+// Tagged 的标签以 #tag 紧跟在消息之后. Loki 标签见 SetLokiLabels
+func (log *Logger) formatText(templates map[LogLevel]string, e *LogEntry) string {
+	fields := e.Fields
+	component := ""
+	if f, ok := templateFor(templates, e.Level); ok && strings.Contains(f, "{{component}}") {
+		component, fields = takeField(fields, componentKey)
+	}
+	labels, fields := log.splitLokiLabels(fields)
+	tags, fields := splitTags(fields)
+	line := log.formatWith(templates, e.Time, e.Level, e.Message+tags+fieldsText(fields, log.typedTextFields))
+	line = strings.Replace(line, "{{component}}", component, -1)
+	line = strings.Replace(line, "{{caller}}", e.Caller.short(), -1)
+	if len(labels) == 0 {
+		return strings.Replace(line, "{{labels}}", "", -1)
+	}
+	if f, _ := templateFor(templates, e.Level); strings.Contains(f, "{{labels}}") {
+		return strings.Replace(line, "{{labels}}", lokiLabelText(labels), -1)
+	}
+	return lokiLabelText(labels) + " " + line
+}
+
+func templateFor(templates map[LogLevel]string, level LogLevel) (string, bool) {
+	if f, ok := templates[level]; ok {
+		return f, true
+	}
+	f, ok := DefaultFormatterMap[level]
+	return f, ok
+}
+
+// formatWith 渲染文本日志, t 为日志时间, 使用默认 SuffixFunc 时 {{suffix}} 中的时间取自 t.
+// 模板支持 {{suffix}}、{{prefix}}、{{host}}、{{pid}}、{{level}} (等级名称) 与 {{time}} (按 SetTimeFormat 格式化的 t),
+// 其他 {{...}} 原样保留
+func (log *Logger) formatWith(templates map[LogLevel]string, t time.Time, level LogLevel, s ...interface{}) string {
 	var line string
-	if f, ok := log.formatter[level]; ok {
-		line = fmt.Sprintf(f, s...)
-	} else if f, ok := DefaultFormatterMap[level]; ok {
-		line = fmt.Sprintf(f, s...)
+	if f, ok := templateFor(templates, level); ok {
+		line = formatMessage(f, s...)
 	} else {
 		line = fmt.Sprintf("[%s] %s ", append([]interface{}{level.Name()}, s...)...)
 	}
-	line = strings.Replace(line, "{{suffix}}", log.SuffixFunc(), -1)
+	line = strings.Replace(line, "{{suffix}}", log.suffix(t), -1)
 	line = strings.Replace(line, "{{prefix}}", log.PrefixFunc(), -1)
+	line = strings.Replace(line, "{{host}}", log.hostname, -1)
+	line = strings.Replace(line, "{{pid}}", log.pid, -1)
+	line = strings.Replace(line, "{{level}}", log.levelName(level), -1)
+	if strings.Contains(line, "{{time}}") {
+		line = strings.Replace(line, "{{time}}", log.timeText(t), -1)
+	}
 	return line
 }
 
+// formatMessage 将消息填入模板, %s 与 {{msg}} 可以同时使用, 都替换为消息.
+// 模板中没有 %s 等格式动词时不经过 Sprintf, 只将 %% 还原为 %, 避免输出 %!(EXTRA ...)
+func formatMessage(f string, s ...interface{}) string {
+	if !strings.Contains(f, "{{msg}}") {
+		return fmt.Sprintf(f, s...)
+	}
+	if strings.Contains(strings.Replace(f, "%%", "", -1), "%") {
+		f = fmt.Sprintf(f, s...)
+	} else {
+		f = strings.Replace(f, "%%", "%", -1)
+	}
+	return strings.Replace(f, "{{msg}}", fmt.Sprint(s...), -1)
+}
+
+// writeToFile 写入日志文件. 同步写入时调用方持有 mu 读锁, 错误推迟到释放锁之后由 reportErrors 报告
 func (log *Logger) writeToFile(line string) {
+	if err := log.writeFile(line); err != nil {
+		log.setWriteError(err)
+		log.deferError(err)
+	}
+}
+
+// writeError 记录写日志文件失败的错误 (见 LastWriteError) 并交给错误处理函数, 调用方不能持有 mu
+func (log *Logger) writeError(err error) {
+	log.setWriteError(err)
+	log.handleError(err)
+}
+
+func (log *Logger) setWriteError(err error) {
+	log.errMu.Lock()
+	log.lastWriteErr = err
+	log.errMu.Unlock()
+}
+
+func (log *Logger) writeFile(line string) error {
 	log.muf.Lock()
 	defer log.muf.Unlock()
-	// 检查 logFile 是否已初始化
+	// 检查 logFile 是否已初始化. 直接设置 LogToFile 与 LogFileName 字段而没有调用 SetIsLogToFile 时,
+	// 在第一次写入时打开文件; Close 之后不再自动打开
 	if log.logFile == nil {
-		// 如果日志文件未初始化，打印警告
-		fmt.Println("Error: Log file is not initialized.")
-		return
+		if log.LogFileName == "" || log.fileClosed {
+			return errors.New("log file is not initialized")
+		}
+		if err := log.openLogFile(); err != nil {
+			return err
+		}
+	}
+
+	if err := log.reopenIfDeleted(); err != nil {
+		return err
+	}
+	if err := log.rotateDailyIfNeeded(); err != nil {
+		return err
+	}
+	if log.rotateSize > 0 && log.fileSize > 0 && log.fileSize+int64(len(line)) > log.rotateSize {
+		if err := log.rotate(); err != nil {
+			return err
+		}
 	}
 
 	// 写入日志到文件
-	_, err := log.logFile.WriteString(line)
+	locked := lockGlobalWrite()
+	n, err := log.logFile.WriteString(line)
+	unlockGlobalWrite(locked)
+	log.fileSize += int64(n)
 	if err != nil {
-		// 写入失败时的错误处理
-		fmt.Printf("Error writing to logfile: %s\n", err.Error())
-		return
+		return fmt.Errorf("writing to logfile: %w", err)
 	}
+	if log.syncOnWrite {
+		if err := log.logFile.Sync(); err != nil {
+			return fmt.Errorf("syncing logfile: %w", err)
+		}
+	}
+	return log.truncateIfNeeded()
 }
-func (log *Logger) Close(remove bool) {
+
+// Close 关闭日志文件. 不传参时按 removeOnClose (默认 false) 决定是否删除文件,
+// 传入 remove 则以显式参数为准
+func (log *Logger) Close(remove ...bool) {
+	// 心跳 goroutine 写日志时需要读锁, 必须在加锁前停止
+	log.StopHeartbeat()
+	// 先写完异步队列, 保证汇总在所有日志之后且日志文件关闭前写完
+	log.StopAsync()
+	log.StopOTLP()
+	// 等待后台压缩完成, 避免进程退出时留下不完整的 .gz
+	log.compressWG.Wait()
+	log.writeSummary()
 
 	log.mu.Lock()
-	defer log.mu.Unlock()
+	rm := log.removeOnClose
+	if len(remove) > 0 {
+		rm = remove[0]
+	}
 
-	// 关闭日志文件
+	// 关闭日志文件. DrainTimeout 超时后后台 goroutine 可能仍在 writeFile 中, 需持有 muf
+	log.muf.Lock()
 	if log.logFile != nil {
 		log.logFile.Close()
 		log.logFile = nil
 	}
+	log.fileClosed = true
+	log.muf.Unlock()
+	log.closed = true
+
+	// 注销事件日志源
+	if log.eventLog != nil {
+		log.eventLog.Close()
+		log.eventLog = nil
+	}
+
+	// 关闭内存映射文件
+	var mmapErr error
+	if log.mmapFile != nil {
+		mmapErr = log.mmapFile.Close()
+		log.mmapFile = nil
+	}
 
 	// 删除日志文件
-	if remove {
-		err := os.Remove(log.LogFileName)
-		if err != nil {
-			fmt.Printf("Error removing logfile: " + err.Error())
-		}
+	var err error
+	if rm {
+		err = os.Remove(log.LogFileName)
+	}
+	pidErr := log.removePIDFile()
+	log.mu.Unlock()
+
+	if err != nil {
+		log.handleError(fmt.Errorf("removing logfile: %w", err))
+	}
+	if pidErr != nil {
+		log.handleError(pidErr)
+	}
+	if mmapErr != nil {
+		log.handleError(fmt.Errorf("closing mmap file: %w", mmapErr))
 	}
 }
 
+// suffix 返回 {{suffix}} 的内容. 默认 SuffixFunc 只能读取当前时间, 为了让 LogAt 等指定时间的日志
+// 显示正确的时间, 识别出默认函数时直接格式化日志时间 t
+func (log *Logger) suffix(t time.Time) string {
+	if log.defaultSuffix() {
+		return ", " + formatCurtime(t)
+	}
+	return log.SuffixFunc()
+}
+
+// defaultSuffix 判断 SuffixFunc 是否仍为默认的时间后缀
+func (log *Logger) defaultSuffix() bool {
+	return reflect.ValueOf(log.SuffixFunc).Pointer() == reflect.ValueOf(defaultSuffix).Pointer()
+}
+
+func defaultSuffix() string {
+	return ", " + getCurtime()
+}
+
 // 获取当前时间
 func getCurtime() string {
-	curtime := time.Now().Format("2006-01-02 15:04.05")
-	return curtime
+	return formatCurtime(time.Now())
+}
+
+func formatCurtime(t time.Time) string {
+	return t.Format("2006-01-02 15:04.05")
 }
@@ -1,7 +1,15 @@
 package gologs
 
 import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestLogger_Console(t *testing.T) {
@@ -22,3 +30,551 @@ func TestLogger_Console(t *testing.T) {
 	AddLevel(1, "test")
 	Log.Log(1, "test")
 }
+
+func TestLogger_CloseDefaultKeepsFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "keep.log")
+	log, err := NewFileLogger(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Warn("keep")
+	log.Close()
+
+	if _, err := os.Stat(filename); err != nil {
+		t.Fatalf("expected log file to be kept, got %v", err)
+	}
+}
+
+func TestLogger_CloseExplicitRemove(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "remove.log")
+	log, err := NewFileLogger(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Warn("remove")
+	log.Close(true)
+
+	if _, err := os.Stat(filename); !os.IsNotExist(err) {
+		t.Fatalf("expected log file to be removed, got %v", err)
+	}
+}
+
+func TestLogger_CloseRemoveOnCloseOption(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "option.log")
+	log, err := NewFileLogger(filename, WithRemoveOnClose(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Close(false)
+	if _, err := os.Stat(filename); err != nil {
+		t.Fatalf("explicit Close(false) should keep the file, got %v", err)
+	}
+
+	log, err = NewFileLogger(filename, WithRemoveOnClose(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Close()
+	if _, err := os.Stat(filename); !os.IsNotExist(err) {
+		t.Fatalf("expected log file to be removed by default policy, got %v", err)
+	}
+}
+
+func TestLogger_NilText(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+
+	log.Warn(nil)
+	if got := buf.String(); got != "[Warn]  \n" {
+		t.Fatalf("unexpected nil rendering: %q", got)
+	}
+
+	buf.Reset()
+	log.SetNilText("<none>")
+	log.Info(nil)
+	if got := buf.String(); !strings.HasPrefix(got, "[-] <none> ") {
+		t.Fatalf("expected nil placeholder, got %q", got)
+	}
+}
+
+func TestLogger_SkipNilError(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+
+	log.Error(nil)
+	if buf.Len() == 0 {
+		t.Fatal("Error(nil) should still emit by default")
+	}
+
+	buf.Reset()
+	log.SetSkipNilError(true)
+	log.Error(nil)
+	if buf.Len() != 0 {
+		t.Fatalf("Error(nil) should be skipped, got %q", buf.String())
+	}
+	log.Error("real error")
+	if !strings.Contains(buf.String(), "real error") {
+		t.Fatalf("non-nil error should still emit, got %q", buf.String())
+	}
+}
+
+func TestLogger_FormatMsgPlaceholder(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SuffixFunc = func() string { return "12:00" }
+	log.SetFormatter(map[LogLevel]string{
+		Warn: "[W] {{suffix}} | {{msg}}\n",
+		Info: "[I] %s {{suffix}}\n",
+	})
+
+	log.Warnf("disk %d%% full", 90)
+	if got := buf.String(); got != "[W] 12:00 | disk 90% full\n" {
+		t.Fatalf("unexpected {{msg}} output: %q", got)
+	}
+
+	buf.Reset()
+	log.Info("legacy")
+	if got := buf.String(); got != "[I] legacy 12:00\n" {
+		t.Fatalf("unexpected %%s output: %q", got)
+	}
+
+	// %s 与 {{msg}} 同时使用, %% 仍还原为 %
+	buf.Reset()
+	log.SetFormatter(map[LogLevel]string{Error: "[E] %s {{suffix}} 100%% | {{msg}}\n"})
+	log.Error("both")
+	if got := buf.String(); got != "[E] both 12:00 100% | both\n" {
+		t.Fatalf("unexpected %%s with {{msg}} output: %q", got)
+	}
+
+	buf.Reset()
+	log.SetFormatter(map[LogLevel]string{Error: "[E] 100%% {{msg}}\n"})
+	log.Error("only msg")
+	if got := buf.String(); got != "[E] 100% only msg\n" {
+		t.Fatalf("unexpected %%%% with {{msg}} output: %q", got)
+	}
+}
+
+func TestLogger_ErrorsToStderr(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stderr := os.Stderr
+	os.Stderr = w
+	defer func() { os.Stderr = stderr }()
+
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetErrorsToStderr(true)
+
+	log.Info("to writer")
+	log.Error("to stderr")
+	w.Close()
+
+	got, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "[Error] to stderr \n" {
+		t.Fatalf("expected error on stderr, got %q", got)
+	}
+	if out := buf.String(); !strings.Contains(out, "to writer") || strings.Contains(out, "to stderr") {
+		t.Fatalf("unexpected default writer output: %q", out)
+	}
+}
+
+func TestLogger_SetConsoleLevel(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+
+	log.Console("banner\n")
+	if got := buf.String(); got != "banner\n" {
+		t.Fatalf("expected console output by default, got %q", got)
+	}
+
+	buf.Reset()
+	log.SetConsoleLevel(Warn)
+	log.Console("banner\n")
+	log.Consolef("%s\n", "progress")
+	log.FConsolef(&buf, "%s\n", "explicit")
+	if buf.Len() != 0 {
+		t.Fatalf("expected console output to be gated, got %q", buf.String())
+	}
+
+	log.SetConsoleLevel(Info)
+	log.Named("ui").Consolef("%s\n", "progress")
+	if got := buf.String(); got != "progress\n" {
+		t.Fatalf("expected console output at the implied level, got %q", got)
+	}
+}
+
+func TestLogger_ReportHostname(t *testing.T) {
+	want, err := os.Hostname()
+	if err != nil || want == "" {
+		want = "unknown"
+	}
+
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetFormatter(map[LogLevel]string{Warn: "{{host}} %s\n"})
+	log.SetReportHostname(true)
+
+	log.Warn("text")
+	if got := buf.String(); got != want+" text\n" {
+		t.Fatalf("expected hostname in text output, got %q", got)
+	}
+
+	buf.Reset()
+	log.SetJSON(true)
+	log.Warn("json")
+	if !strings.Contains(buf.String(), `"host":"`+want+`"`) {
+		t.Fatalf("expected host field in json output, got %q", buf.String())
+	}
+}
+
+func TestLogger_ReportPID(t *testing.T) {
+	pid := strconv.Itoa(os.Getpid())
+
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetFormatter(map[LogLevel]string{Warn: "[{{pid}}] %s\n"})
+	log.SetReportPID(true)
+
+	log.Warn("text")
+	if got := buf.String(); got != "["+pid+"] text\n" {
+		t.Fatalf("expected pid in text output, got %q", got)
+	}
+
+	buf.Reset()
+	log.SetJSON(true)
+	log.Warn("json")
+	if !strings.Contains(buf.String(), `"pid":`+pid+`,`) {
+		t.Fatalf("expected numeric pid field in json output, got %q", buf.String())
+	}
+
+	buf.Reset()
+	log.SetReportPID(false)
+	log.Warn("off")
+	if strings.Contains(buf.String(), `"pid"`) {
+		t.Fatalf("expected no pid field when disabled, got %q", buf.String())
+	}
+}
+
+func TestLogger_FileTruncate(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "run.log")
+	write := func(msg string, truncate bool) {
+		log := NewLogger(Debug)
+		log.SetOutput(io.Discard)
+		log.SetFile(filename)
+		log.SetFileTruncate(truncate)
+		log.SetIsLogToFile(true)
+		log.Warn(msg)
+		log.Close()
+	}
+
+	write("first", false)
+	write("second", false)
+	if got, _ := os.ReadFile(filename); string(got) != "[Warn] first \n[Warn] second \n" {
+		t.Fatalf("expected appended content, got %q", got)
+	}
+
+	write("third", true)
+	if got, _ := os.ReadFile(filename); string(got) != "[Warn] third \n" {
+		t.Fatalf("expected truncated content, got %q", got)
+	}
+
+	log, err := NewFileLogger(filename, WithFileTruncate(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	log.Warn("fourth")
+	log.Close()
+	if got, _ := os.ReadFile(filename); string(got) != "[Warn] fourth \n" {
+		t.Fatalf("expected NewFileLogger to truncate, got %q", got)
+	}
+}
+
+func TestNewFileLogger_Appends(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "daemon.log")
+	for _, msg := range []string{"run one", "run two"} {
+		log, err := NewFileLogger(filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		log.Warn(msg)
+		log.Close()
+	}
+
+	if got, _ := os.ReadFile(filename); string(got) != "[Warn] run one \n[Warn] run two \n" {
+		t.Fatalf("expected content to accumulate across runs, got %q", got)
+	}
+}
+
+func TestLogger_LogAt(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	at := time.Date(2001, 2, 3, 4, 5, 6, 0, time.UTC)
+
+	log.LogAt(at, Info, "imported")
+	if got := buf.String(); got != "[-] imported , 2001-02-03 04:05.06\n" {
+		t.Fatalf("expected explicit timestamp in suffix, got %q", got)
+	}
+
+	buf.Reset()
+	log.SetJSON(true)
+	log.LogAtf(at, Warn, "imported %d", 2)
+	if !strings.Contains(buf.String(), `"time":"2001-02-03T04:05:06Z"`) {
+		t.Fatalf("expected explicit timestamp in json, got %q", buf.String())
+	}
+
+	buf.Reset()
+	log.LogAt(at, Debug-1, "filtered")
+	if buf.Len() != 0 {
+		t.Fatalf("expected LogAt to respect level, got %q", buf.String())
+	}
+}
+
+func TestLogger_ForceAll(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Error)
+	log.SetOutput(&buf)
+	log.SetQuiet(true)
+
+	log.Debug("hidden")
+	if buf.Len() != 0 {
+		t.Fatalf("expected no output without force-all, got %q", buf.String())
+	}
+
+	log.SetForceAll(true)
+	log.Debug("forced")
+	if got := buf.String(); got != "[Debug] forced \n" {
+		t.Fatalf("expected debug output with force-all, got %q", got)
+	}
+}
+
+func TestLogger_LazyLogFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "lazy.log")
+	log := NewLogger(Debug)
+	log.SetOutput(io.Discard)
+	var reported []error
+	log.SetErrorHandler(func(err error) { reported = append(reported, err) })
+	log.LogFileName = filename
+	log.LogToFile = true
+
+	if _, err := os.Stat(filename); !os.IsNotExist(err) {
+		t.Fatalf("expected no file before the first write, got %v", err)
+	}
+	log.Warn("first")
+	log.Warn("second")
+	log.Close()
+	log.Warn("after close")
+
+	if got, _ := os.ReadFile(filename); string(got) != "[Warn] first \n[Warn] second \n" {
+		t.Fatalf("expected file created on first write, got %q", got)
+	}
+	if len(reported) != 1 {
+		t.Fatalf("expected only the write after Close to fail, got %v", reported)
+	}
+}
+
+func TestLogger_ConcurrentSetters(t *testing.T) {
+	log := NewLogger(Debug)
+	log.SetOutput(io.Discard)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			log.SetLevel(LogLevel(i%3*10 + 10))
+			log.SetColor(i%2 == 0)
+			log.SetQuiet(i%5 == 0)
+			log.SetClean(i%7 == 0)
+			log.SetForceAll(i%11 == 0)
+			log.SetOutput(io.Discard)
+			_ = log.GetLevel()
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		log.Warn("concurrent")
+		log.Named("child").Infof("%d", i)
+		log.Console("banner\n")
+	}
+	<-done
+
+	log.SetLevel(Warn)
+	if got := log.GetLevel(); got != Warn {
+		t.Fatalf("expected GetLevel to return Warn, got %d", got)
+	}
+}
+
+func TestLogger_ConcurrentFormatSetters(t *testing.T) {
+	log := NewLogger(Debug)
+	log.SetOutput(io.Discard)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			log.SetJSON(i%2 == 0)
+			log.SetJSONIndent(i%3 == 0)
+			log.SetJSONSortKeys(i%5 == 0)
+			log.SetJSONTimeFormat(time.RFC3339)
+			log.SetJSONEpochTime(EpochUnit(i % 2))
+			log.SetNilText("<nil>")
+			log.SetReportCaller(i%7 == 0)
+			log.SetConsoleLevel(LogLevel(i % 50))
+			log.SetPreWriteHook(func(_ LogLevel, line string) (string, bool) { return line, true })
+			log.SetSkipNilError(i%2 == 0)
+			log.SetReportHostname(i%3 == 0)
+			log.SetReportPID(i%3 == 0)
+			log.SetErrorHandler(func(error) {})
+			log.SetInvalidUTF8Policy(InvalidUTF8Policy(i % 3))
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		log.Warn(nil)
+		log.Error(nil)
+		log.InfoFields("fields", map[string]interface{}{"i": i})
+		log.Console("banner\n")
+	}
+	<-done
+}
+
+func TestLogger_ConcurrentInitLogFile(t *testing.T) {
+	log := NewLogger(Debug)
+	log.SetOutput(io.Discard)
+	log.SetFile(filepath.Join(t.TempDir(), "reinit.log"))
+	log.SetIsLogToFile(true)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			log.InitLogFile()
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		log.Info("concurrent")
+	}
+	<-done
+	log.Close()
+}
+
+func TestLogger_SetErrorOutput(t *testing.T) {
+	var out, errOut, explicit bytes.Buffer
+	filename := filepath.Join(t.TempDir(), "routed.log")
+	log := NewLogger(Debug)
+	log.SetOutput(&out)
+	log.SetFile(filename)
+	log.SetIsLogToFile(true)
+
+	log.Error("default")
+	if out.String() != "[Error] default \n" {
+		t.Fatalf("expected everything on writer by default, got %q", out.String())
+	}
+
+	out.Reset()
+	log.SetErrorOutput(&errOut)
+	log.SetErrorThreshold(Warn)
+	log.Info("info")
+	log.Warn("warn")
+	log.Error("error")
+	log.FErrorf(&explicit, "%s", "explicit")
+	log.Close()
+
+	if got := out.String(); !strings.HasPrefix(got, "[-] info ") || strings.Count(got, "\n") != 1 {
+		t.Fatalf("expected only info on writer, got %q", got)
+	}
+	if got := errOut.String(); got != "[Warn] warn \n[Error] error \n" {
+		t.Fatalf("expected warn and error on error output, got %q", got)
+	}
+	if got := explicit.String(); got != "[Error] explicit \n" {
+		t.Fatalf("expected F* methods to keep their writer, got %q", got)
+	}
+	file, _ := os.ReadFile(filename)
+	for _, want := range []string{"default", "info", "warn", "error", "explicit"} {
+		if !strings.Contains(string(file), want) {
+			t.Fatalf("expected %q in log file, got %q", want, file)
+		}
+	}
+}
+
+// expensiveStringer 模拟格式化开销较大的参数, 记录 String 被调用的次数
+type expensiveStringer struct{ calls *int }
+
+func (e expensiveStringer) String() string {
+	*e.calls++
+	return strings.Repeat("x", 256)
+}
+
+func TestLogger_FLogfSkipsFormattingBelowLevel(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Warn)
+	calls := 0
+	arg := expensiveStringer{&calls}
+
+	log.FLogf(&buf, Debug, "dropped", arg)
+	log.KV(Debug, map[string]interface{}{"k": arg})
+	log.Diff(Debug, "cfg", map[string]interface{}{"k": 1}, map[string]interface{}{"k": arg})
+	if calls != 0 || buf.Len() != 0 {
+		t.Fatalf("expected no formatting below the level, got %d String calls and %q", calls, buf.String())
+	}
+
+	log.FLogf(&buf, Warn, "kept", 1)
+	if got := buf.String(); got != "[Warn] kept 1\n \n" {
+		t.Fatalf("expected FLogf output, got %q", got)
+	}
+}
+
+func BenchmarkFLogfBelowLevel(b *testing.B) {
+	calls := 0
+	arg := expensiveStringer{&calls}
+	log := NewLogger(Warn)
+	log.SetOutput(io.Discard)
+
+	b.Run("lazy", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			log.FLogf(io.Discard, Debug, "payload", arg)
+		}
+	})
+	// eager 对应改动前先 Sprintln 再判断等级的做法, 作为对照
+	b.Run("eager", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			log.logInterface(io.Discard, Debug, nil, fmt.Sprintln("payload", arg))
+		}
+	})
+}
+
+func TestLogger_LevelAndTimeTokens(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetClock(func() time.Time { return time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC) })
+	log.SetFormatter(map[LogLevel]string{
+		Warn:  "{{time}} {{level}} {{msg}} {{unknown}}\n",
+		Error: "{{level}}|%s|{{time}}\n",
+	})
+
+	log.Warn("disk")
+	if got := buf.String(); got != "2024-03-04 05:06.07 Warn disk {{unknown}}\n" {
+		t.Fatalf("unexpected token expansion %q", got)
+	}
+
+	buf.Reset()
+	log.SetTimeFormat(time.RFC3339)
+	log.Error("down")
+	if got := buf.String(); got != "Error|down|2024-03-04T05:06:07Z\n" {
+		t.Fatalf("expected the configured time format, got %q", got)
+	}
+
+	if got := log.Format(Warn, "direct"); got != "2024-03-04T05:06:07Z Warn direct {{unknown}}\n" {
+		t.Fatalf("expected Format to expand tokens, got %q", got)
+	}
+}
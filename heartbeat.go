@@ -0,0 +1,48 @@
+package gologs
+
+import "time"
+
+// StartHeartbeat 按 interval 周期以 level 输出 msg, 用于在长时间空闲时证明进程存活.
+// 重复调用会先停止上一个心跳, 调用 StopHeartbeat 或 Close 时停止.
+// interval <= 0 时等同于 StopHeartbeat, 不会启动新的心跳
+func (log *Logger) StartHeartbeat(interval time.Duration, level LogLevel, msg string) {
+	log.hbMu.Lock()
+	defer log.hbMu.Unlock()
+	log.stopHeartbeat()
+	if interval <= 0 {
+		return
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	log.hbStop, log.hbDone = stop, done
+	go func() {
+		defer close(done)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				log.Log(level, msg)
+			}
+		}
+	}()
+}
+
+// StopHeartbeat 停止心跳并等待后台 goroutine 退出
+func (log *Logger) StopHeartbeat() {
+	log.hbMu.Lock()
+	defer log.hbMu.Unlock()
+	log.stopHeartbeat()
+}
+
+func (log *Logger) stopHeartbeat() {
+	if log.hbStop == nil {
+		return
+	}
+	close(log.hbStop)
+	<-log.hbDone
+	log.hbStop, log.hbDone = nil, nil
+}
@@ -0,0 +1,96 @@
+package gologs
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer 是并发安全的 bytes.Buffer, 供后台 goroutine 写日志的测试使用
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+func TestLogger_Heartbeat(t *testing.T) {
+	buf := &syncBuffer{}
+	log := NewLogger(Debug)
+	log.SetOutput(buf)
+
+	log.StartHeartbeat(5*time.Millisecond, Info, "alive")
+	time.Sleep(40 * time.Millisecond)
+	log.StopHeartbeat()
+
+	n := strings.Count(buf.String(), "alive")
+	if n < 2 {
+		t.Fatalf("expected at least 2 heartbeats, got %d", n)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if after := strings.Count(buf.String(), "alive"); after != n {
+		t.Fatalf("heartbeat kept running after stop: %d -> %d", n, after)
+	}
+}
+
+func TestLogger_HeartbeatStopsOnClose(t *testing.T) {
+	buf := &syncBuffer{}
+	log := NewLogger(Debug)
+	log.SetOutput(buf)
+
+	log.StartHeartbeat(5*time.Millisecond, Info, "alive")
+	time.Sleep(20 * time.Millisecond)
+	log.hbMu.Lock()
+	done := log.hbDone
+	log.hbMu.Unlock()
+	log.Close()
+
+	// Close 之后日志本就不再输出, 只能通过 goroutine 是否退出判断心跳已停止
+	select {
+	case <-done:
+	default:
+		t.Fatal("expected the heartbeat goroutine to exit on close")
+	}
+	if log.hbStop != nil || log.hbDone != nil {
+		t.Fatal("expected the heartbeat to be cleared on close")
+	}
+	if !strings.Contains(buf.String(), "alive") {
+		t.Fatalf("expected heartbeat lines before close, got %q", buf.String())
+	}
+}
+
+func TestLogger_HeartbeatNonPositiveIntervalStops(t *testing.T) {
+	buf := &syncBuffer{}
+	log := NewLogger(Debug)
+	log.SetOutput(buf)
+
+	log.StartHeartbeat(5*time.Millisecond, Info, "alive")
+	log.StartHeartbeat(0, Info, "alive")
+	log.StartHeartbeat(-time.Second, Info, "alive")
+
+	log.hbMu.Lock()
+	running := log.hbStop != nil
+	log.hbMu.Unlock()
+	if running {
+		t.Fatal("expected non-positive interval to stop the heartbeat")
+	}
+
+	n := strings.Count(buf.String(), "alive")
+	time.Sleep(20 * time.Millisecond)
+	if after := strings.Count(buf.String(), "alive"); after != n {
+		t.Fatalf("heartbeat kept running: %d -> %d", n, after)
+	}
+}
@@ -0,0 +1,24 @@
+package gologs
+
+import "fmt"
+
+// AddHook 注册一个钩子, 每条通过等级过滤并实际输出的日志都会以等级和消息调用它, 与颜色、文件等输出设置无关,
+// 可用于按等级累加 Prometheus 计数器或将错误转发到 Sentry. 可注册多个, 按注册顺序调用.
+// 钩子在释放 Logger 的锁之后执行, 调用时使用注册列表的快照, 并发注册是安全的;
+// 钩子 panic 时被恢复并交给错误处理函数, 不影响日志调用和其他钩子
+func (log *Logger) AddHook(hook func(level LogLevel, msg string)) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	hooks := make([]func(LogLevel, string), len(log.hooks), len(log.hooks)+1)
+	copy(hooks, log.hooks)
+	log.hooks = append(hooks, hook)
+}
+
+func (log *Logger) runHook(hook func(LogLevel, string), e *LogEntry) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.handleError(fmt.Errorf("log hook panic: %v", r))
+		}
+	}()
+	hook(e.Level, e.Message)
+}
@@ -0,0 +1,60 @@
+package gologs
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+)
+
+func TestLogger_AddHook(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Info)
+	log.SetOutput(&buf)
+	var reported []error
+	log.SetErrorHandler(func(err error) { reported = append(reported, err) })
+
+	counts := make(map[LogLevel]int)
+	var msgs []string
+	log.AddHook(func(level LogLevel, msg string) { panic("broken hook") })
+	log.AddHook(func(level LogLevel, msg string) {
+		counts[level]++
+		msgs = append(msgs, msg)
+	})
+
+	log.Debug("filtered")
+	log.Warn("w1")
+	log.Named("db").Errorf("e%d", 1)
+	log.Warn("w2")
+
+	if counts[Warn] != 2 || counts[Error] != 1 || counts[Debug] != 0 {
+		t.Fatalf("unexpected per-level counts %v", counts)
+	}
+	if len(msgs) != 3 || msgs[1] != "e1" {
+		t.Fatalf("expected messages without formatting, got %q", msgs)
+	}
+	if len(reported) != 3 {
+		t.Fatalf("expected each hook panic to be reported, got %v", reported)
+	}
+	if got := buf.String(); got != "[Warn] w1 \n[Error] e1 component=db \n[Warn] w2 \n" {
+		t.Fatalf("expected logging to continue despite the panicking hook, got %q", got)
+	}
+}
+
+func TestLogger_AddHookConcurrent(t *testing.T) {
+	log := NewLogger(Debug)
+	log.SetOutput(io.Discard)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			log.AddHook(func(LogLevel, string) {})
+		}()
+		go func() {
+			defer wg.Done()
+			log.Warn("concurrent")
+		}()
+	}
+	wg.Wait()
+}
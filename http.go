@@ -0,0 +1,52 @@
+package gologs
+
+import (
+	"net/http"
+	"runtime/debug"
+)
+
+// RecoverMiddleware 返回一个 http 中间件: 捕获 handler 中的 panic, 以 Error 等级记录请求方法、路径与堆栈,
+// 并返回 500. 上游中间件通过 ContextWithFields 放入请求 context 的字段 (如 request_id) 会一并输出.
+// http.ErrAbortHandler 按 net/http 的约定继续向上 panic
+func RecoverMiddleware(base *Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				rec := recover()
+				if rec == nil {
+					return
+				}
+				if rec == http.ErrAbortHandler {
+					panic(rec)
+				}
+
+				ctx := ContextWithFields(r.Context(), map[string]interface{}{
+					"method": r.Method,
+					"path":   r.URL.Path,
+				})
+				base.LogCtxf(ctx, Error, "panic: %v\n%s", rec, debug.Stack())
+				http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// RequestIDHeader 是 RequestIDMiddleware 读取与回写的请求头
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDMiddleware 返回一个 http 中间件: 沿用请求头 X-Request-ID, 没有时用 base.NewID 生成,
+// 以 request_id 字段放入请求 context 并写回响应头, 之后通过 LogCtx/LogCtxf 输出的日志都会带上它
+func RequestIDMiddleware(base *Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(RequestIDHeader)
+			if id == "" {
+				id = base.NewID()
+			}
+			w.Header().Set(RequestIDHeader, id)
+			ctx := ContextWithFields(r.Context(), map[string]interface{}{"request_id": id})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
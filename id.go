@@ -0,0 +1,52 @@
+package gologs
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// SetIDGenerator 设置生成关联 ID 的函数 (例如 ULID、snowflake), 供 NewID 与 RequestIDMiddleware 使用.
+// 为 nil 时恢复默认的随机 UUID (v4)
+func (log *Logger) SetIDGenerator(gen func() string) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.idGenerator = gen
+}
+
+// NewID 使用 SetIDGenerator 设置的函数生成一个关联 ID
+func (log *Logger) NewID() string {
+	log.mu.RLock()
+	gen := log.idGenerator
+	log.mu.RUnlock()
+	if gen == nil {
+		gen = randomID
+	}
+	return gen()
+}
+
+var idFallback atomic.Uint64
+
+// randomID 返回随机的 UUID v4 字符串, 随机源不可用时退化为时间戳加自增序号
+func randomID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36) + "-" + strconv.FormatUint(idFallback.Add(1), 36)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	var s [36]byte
+	hex.Encode(s[0:8], b[0:4])
+	s[8] = '-'
+	hex.Encode(s[9:13], b[4:6])
+	s[13] = '-'
+	hex.Encode(s[14:18], b[6:8])
+	s[18] = '-'
+	hex.Encode(s[19:23], b[8:10])
+	s[23] = '-'
+	hex.Encode(s[24:], b[10:])
+	return string(s[:])
+}
@@ -0,0 +1,67 @@
+package gologs
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"testing"
+)
+
+func TestLogger_NewIDDefaultUnique(t *testing.T) {
+	log := NewLogger(Debug)
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 10000; i++ {
+		id := log.NewID()
+		if !uuid.MatchString(id) {
+			t.Fatalf("expected uuid v4, got %q", id)
+		}
+		if seen[id] {
+			t.Fatalf("duplicate id %q after %d calls", id, i)
+		}
+		seen[id] = true
+	}
+}
+
+func TestLogger_SetIDGenerator(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	n := 0
+	log.SetIDGenerator(func() string {
+		n++
+		return "req-" + strconv.Itoa(n)
+	})
+
+	if got := log.NewID(); got != "req-1" {
+		t.Fatalf("expected custom generator, got %q", got)
+	}
+
+	handler := RequestIDMiddleware(log)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		log.LogCtx(r.Context(), Warn, "handled")
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if got := rec.Header().Get(RequestIDHeader); got != "req-2" {
+		t.Fatalf("expected generated id in response header, got %q", got)
+	}
+	if got := buf.String(); got != "[Warn] handled request_id=req-2 \n" {
+		t.Fatalf("expected request_id field, got %q", got)
+	}
+
+	buf.Reset()
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "upstream")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if got := buf.String(); got != "[Warn] handled request_id=upstream \n" || n != 2 {
+		t.Fatalf("expected incoming id to be kept, got %q (generator calls %d)", got, n)
+	}
+
+	log.SetIDGenerator(nil)
+	if got := log.NewID(); len(got) != 36 {
+		t.Fatalf("expected default generator after reset, got %q", got)
+	}
+}
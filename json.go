@@ -0,0 +1,248 @@
+package gologs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// JSON 输出中由 Logger 自身填充的字段, 同名的结构化字段会被忽略
+const (
+	jsonTimeKey     = "time"
+	jsonLevelKey    = "level"
+	jsonMessageKey  = "message"
+	jsonHostKey     = "host"
+	jsonPIDKey      = "pid"
+	jsonSeverityKey = "severity"
+	jsonPrefixKey   = "prefix"
+	jsonSuffixKey   = "suffix"
+	jsonCallerKey   = "caller"
+	jsonLabelsKey   = "labels"
+)
+
+// SetJSON 开启后每条日志输出为一行 JSON 对象 {"time":...,"level":...,"message":...},
+// 结构化字段作为对象成员输出, 文本格式化模板与颜色设置不再生效
+func (log *Logger) SetJSON(j bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.json = j
+}
+
+// SetJSONIndent 开启后 JSON 日志以缩进的多行形式输出, 便于本地调试时阅读.
+// 缩进输出不再是一行一条 (NDJSON), 只适合人工查看, 不要用于日志采集
+func (log *Logger) SetJSONIndent(indent bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.jsonIndent = indent
+}
+
+// SetMessageKey 设置 JSON 输出中消息字段的键名, 例如 "msg" 或 "@message", 为空时使用 "message" (默认).
+// 与该键同名的结构化字段会被忽略. key 与 Logger 自身填充的字段 ("time"、"level"、"caller" 等以及
+// SetSuccessField 的字段名) 同名时会产生重复的键, 此时不生效, 仍使用 "message"
+func (log *Logger) SetMessageKey(key string) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.messageKey = key
+}
+
+// jsonMessageKeyName 返回当前的消息键名
+func (log *Logger) jsonMessageKeyName() string {
+	if log.messageKey == "" || jsonReservedKey(log.messageKey) || log.messageKey == log.successField {
+		return jsonMessageKey
+	}
+	return log.messageKey
+}
+
+// jsonReservedKey 判断 key 是否为 Logger 自身填充的字段名
+func jsonReservedKey(key string) bool {
+	switch key {
+	case jsonTimeKey, jsonLevelKey, jsonHostKey, jsonPIDKey, jsonSeverityKey,
+		jsonPrefixKey, jsonSuffixKey, jsonCallerKey, jsonLabelsKey:
+		return true
+	}
+	return false
+}
+
+// SetSuccessField 开启后 JSON 输出增加名为 name 的布尔字段 (例如 "ok"): 等级低于 SetSuccessThreshold 的门限
+// (默认 Warn) 时为 true, 否则为 false, 便于直接按成功/失败统计错误率. 为空时不输出 (默认)
+func (log *Logger) SetSuccessField(name string) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.successField = name
+}
+
+// SetSuccessThreshold 设置 SetSuccessField 字段为 false 的最低等级, level <= 0 时恢复默认的 Warn
+func (log *Logger) SetSuccessThreshold(level LogLevel) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.successThreshold = level
+}
+
+func (log *Logger) successLevel() LogLevel {
+	if log.successThreshold <= 0 {
+		return Warn
+	}
+	return log.successThreshold
+}
+
+// SetJSONTimeFormat 设置 JSON 输出中 time 字段的格式 (time.Format 的 layout), 为空时使用 time.RFC3339 (默认)
+func (log *Logger) SetJSONTimeFormat(layout string) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.jsonTimeFormat = layout
+}
+
+// EpochUnit 为 JSON time 字段以 Unix 时间戳输出时的单位
+type EpochUnit int
+
+const (
+	EpochNone    EpochUnit = iota // 按 SetJSONTimeFormat 的格式输出字符串 (默认)
+	EpochSeconds                  // Unix 秒
+	EpochMillis                   // Unix 毫秒
+)
+
+// SetJSONEpochTime 设置 JSON time 字段输出为 unit 单位的整数 Unix 时间戳, EpochNone 恢复字符串格式
+func (log *Logger) SetJSONEpochTime(unit EpochUnit) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.jsonEpoch = unit
+}
+
+// SetJSONSortKeys 开启后 JSON 日志的所有成员 (包括结构化字段) 按键名排序输出,
+// 相同的日志条目总是得到字节相同的输出, 便于快照测试和 diff
+func (log *Logger) SetJSONSortKeys(sorted bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.jsonSortKeys = sorted
+}
+
+// jsonMember 是 JSON 日志对象中的一个成员
+type jsonMember struct {
+	key   string
+	value interface{}
+}
+
+func (log *Logger) formatJSON(e *LogEntry) string {
+	members := []jsonMember{
+		{jsonTimeKey, log.jsonTime(e.Time)},
+		{jsonLevelKey, log.levelName(e.Level)},
+	}
+	if log.severityMapper != nil {
+		members = append(members, jsonMember{jsonSeverityKey, log.severityMapper(e.Level)})
+	}
+	if log.successField != "" {
+		members = append(members, jsonMember{log.successField, e.Level < log.successLevel()})
+	}
+	if log.hostname != "" {
+		members = append(members, jsonMember{jsonHostKey, log.hostname})
+	}
+	if log.pid != "" {
+		members = append(members, jsonMember{jsonPIDKey, json.Number(log.pid)})
+	}
+	// 文本模式通过 {{prefix}}/{{suffix}} 拼接的内容在 JSON 中作为独立字段, 不混入 message.
+	// 默认 SuffixFunc 只输出时间, 已由 time 字段表示
+	if log.PrefixFunc != nil {
+		if prefix := log.PrefixFunc(); prefix != "" {
+			members = append(members, jsonMember{jsonPrefixKey, prefix})
+		}
+	}
+	if log.SuffixFunc != nil && !log.defaultSuffix() {
+		if suffix := log.SuffixFunc(); suffix != "" {
+			members = append(members, jsonMember{jsonSuffixKey, suffix})
+		}
+	}
+	if e.Caller != nil {
+		members = append(members, jsonMember{jsonCallerKey, jsonCaller{e.Caller.File, e.Caller.Line, e.Caller.Function}})
+	}
+	labels, fields := log.splitLokiLabels(e.Fields)
+	if len(labels) > 0 {
+		members = append(members, jsonMember{jsonLabelsKey, lokiLabelMap(labels)})
+	}
+	members = append(members, jsonMember{log.jsonMessageKeyName(), e.Message})
+	reserved := len(members)
+	for _, f := range fields {
+		if containsKey(members[:reserved], f.Key) {
+			continue
+		}
+		members = append(members, jsonMember{f.Key, f.Value})
+	}
+	if log.jsonSortKeys {
+		sort.SliceStable(members, func(i, j int) bool { return members[i].key < members[j].key })
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, m := range members {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		writeJSONMember(&buf, m.key, m.value)
+	}
+	buf.WriteByte('}')
+
+	if log.jsonIndent {
+		var out bytes.Buffer
+		if err := json.Indent(&out, buf.Bytes(), "", "  "); err == nil {
+			buf = out
+		}
+	}
+	buf.WriteByte('\n')
+	return buf.String()
+}
+
+// jsonTime 返回 time 字段的值, 按 SetJSONEpochTime 与 SetJSONTimeFormat 的设置输出数字或字符串
+func (log *Logger) jsonTime(t time.Time) interface{} {
+	switch log.jsonEpoch {
+	case EpochSeconds:
+		return t.Unix()
+	case EpochMillis:
+		return t.UnixMilli()
+	}
+	if log.jsonTimeFormat == "" {
+		return t.Format(time.RFC3339)
+	}
+	return t.Format(log.jsonTimeFormat)
+}
+
+// jsonCaller 是调用位置在 JSON 中的表示
+type jsonCaller struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Function string `json:"function"`
+}
+
+func containsKey(members []jsonMember, key string) bool {
+	for _, m := range members {
+		if m.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func writeJSONMember(buf *bytes.Buffer, key string, value interface{}) {
+	k, _ := json.Marshal(key)
+	buf.Write(k)
+	buf.WriteByte(':')
+	v, err := json.Marshal(jsonValue(value))
+	if err != nil {
+		v, _ = json.Marshal(fmt.Sprint(value))
+	}
+	buf.Write(v)
+}
+
+// jsonValue 将 json.Marshal 默认输出不可读的类型转换为字符串
+func jsonValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case time.Duration:
+		return v.String()
+	case time.Time:
+		return v.Format(time.RFC3339)
+	case error:
+		return v.Error()
+	default:
+		return v
+	}
+}
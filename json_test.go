@@ -0,0 +1,274 @@
+package gologs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogger_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetColor(true)
+	log.SetJSON(true)
+
+	log.Warnf("disk %s", "full")
+	if err := ValidateJSONOutput(buf.String(), "time:string", "level:string", "message:string"); err != nil {
+		t.Fatalf("%v: %q", err, buf.String())
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &obj); err != nil {
+		t.Fatal(err)
+	}
+	if obj["level"] != "Warn" || obj["message"] != "disk full" {
+		t.Fatalf("unexpected json entry: %v", obj)
+	}
+}
+
+func TestLogger_JSONDurationAndTime(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetJSON(true)
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	ctx := ContextWithFields(context.Background(), map[string]interface{}{
+		"elapsed": 1500 * time.Millisecond,
+		"at":      at,
+	})
+	log.LogCtx(ctx, Warn, "done")
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &obj); err != nil {
+		t.Fatal(err)
+	}
+	if obj["elapsed"] != "1.5s" {
+		t.Fatalf("expected duration string, got %v", obj["elapsed"])
+	}
+	if obj["at"] != "2024-01-02T03:04:05Z" {
+		t.Fatalf("expected RFC3339 time, got %v", obj["at"])
+	}
+}
+
+func TestLogger_JSONIndent(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetJSON(true)
+
+	log.Warn("compact")
+	if got := buf.String(); strings.Count(got, "\n") != 1 || strings.Contains(got, "  ") {
+		t.Fatalf("expected single-line compact json, got %q", got)
+	}
+
+	buf.Reset()
+	log.SetJSONIndent(true)
+	log.Warn("indented")
+	got := buf.String()
+	if strings.Count(got, "\n") < 4 || !strings.Contains(got, "\n  \"message\": \"indented\"") {
+		t.Fatalf("expected indented json, got %q", got)
+	}
+	if err := ValidateJSONOutput(got, "message"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLogger_JSONPrefixSuffixFields(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetJSON(true)
+
+	log.Info("default")
+	var obj map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &obj); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := obj["suffix"]; ok {
+		t.Fatalf("default time suffix should not be duplicated: %v", obj)
+	}
+	if _, ok := obj["prefix"]; ok {
+		t.Fatalf("empty prefix should be omitted: %v", obj)
+	}
+
+	buf.Reset()
+	log.PrefixFunc = func() string { return "[svc]" }
+	log.SuffixFunc = func() string { return "node-1" }
+	log.Info("clean")
+	obj = nil
+	if err := json.Unmarshal(buf.Bytes(), &obj); err != nil {
+		t.Fatal(err)
+	}
+	if obj["message"] != "clean" || obj["prefix"] != "[svc]" || obj["suffix"] != "node-1" {
+		t.Fatalf("expected prefix and suffix as separate fields, got %v", obj)
+	}
+}
+
+func TestLogger_JSONSortKeys(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetJSON(true)
+	log.SetJSONSortKeys(true)
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	log.SetClock(func() time.Time { return at })
+
+	entry := func() string {
+		buf.Reset()
+		ctx := ContextWithFields(context.Background(), map[string]interface{}{
+			"zone":  "eu",
+			"attrs": map[string]interface{}{"b": 2, "a": 1},
+			"id":    7,
+		})
+		log.With(map[string]interface{}{"service": "api"}).LogCtx(ctx, Warn, "snapshot")
+		return buf.String()
+	}
+
+	first, second := entry(), entry()
+	want := `{"attrs":{"a":1,"b":2},"id":7,"level":"Warn","message":"snapshot","service":"api","time":"2024-01-02T03:04:05Z","zone":"eu"}` + "\n"
+	if first != want {
+		t.Fatalf("expected sorted keys, got %q", first)
+	}
+	if first != second {
+		t.Fatalf("expected byte-identical output, got %q and %q", first, second)
+	}
+}
+
+func TestLogger_JSONTimeFormatAndFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "app.json")
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetColor(true)
+	log.SetFile(filename)
+	log.SetIsLogToFile(true)
+	log.SetJSON(true)
+	log.SetJSONTimeFormat(time.RFC3339Nano)
+	at := time.Date(2024, 1, 2, 3, 4, 5, 600000000, time.UTC)
+	log.SetClock(func() time.Time { return at })
+
+	log.Warn("shipped")
+	log.Close()
+
+	want := `{"time":"2024-01-02T03:04:05.6Z","level":"Warn","message":"shipped"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("expected uncolored json with custom time format on console, got %q", got)
+	}
+	if got, _ := os.ReadFile(filename); string(got) != want {
+		t.Fatalf("expected json in log file, got %q", got)
+	}
+}
+
+func TestLogger_JSONEpochTime(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetJSON(true)
+	at := time.Date(2024, 1, 2, 3, 4, 5, 678000000, time.UTC)
+	log.SetClock(func() time.Time { return at })
+
+	for _, tc := range []struct {
+		unit EpochUnit
+		want string
+	}{
+		{EpochSeconds, `{"time":1704164645,`},
+		{EpochMillis, `{"time":1704164645678,`},
+		{EpochNone, `{"time":"2024-01-02T03:04:05Z",`},
+	} {
+		buf.Reset()
+		log.SetJSONEpochTime(tc.unit)
+		log.Warn("epoch")
+		if !strings.HasPrefix(buf.String(), tc.want) {
+			t.Fatalf("unit %d: expected prefix %s, got %q", tc.unit, tc.want, buf.String())
+		}
+	}
+}
+
+func TestLogger_SetMessageKey(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetJSON(true)
+	log.SetMessageKey("msg")
+
+	log.WarnFields("disk full", map[string]interface{}{"msg": "shadowed", "pct": 91})
+	var obj map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &obj); err != nil {
+		t.Fatal(err)
+	}
+	if obj["msg"] != "disk full" || obj["pct"] != float64(91) {
+		t.Fatalf("expected message under the configured key, got %v", obj)
+	}
+	if _, ok := obj["message"]; ok {
+		t.Fatalf("expected no default message key, got %v", obj)
+	}
+
+	buf.Reset()
+	log.SetMessageKey("")
+	log.Warn("default")
+	if !strings.Contains(buf.String(), `"message":"default"`) {
+		t.Fatalf("expected the default message key, got %q", buf.String())
+	}
+
+	// 与 Logger 自身填充的字段同名的键名不生效, 避免重复的键
+	log.SetSuccessField("ok")
+	log.SetReportCaller(true)
+	for _, key := range []string{"time", "level", "caller", "ok"} {
+		buf.Reset()
+		log.SetMessageKey(key)
+		log.Warn("reserved")
+		if strings.Count(buf.String(), `"`+key+`":`) != 1 || !strings.Contains(buf.String(), `"message":"reserved"`) {
+			t.Fatalf("expected reserved key %q to fall back to message, got %q", key, buf.String())
+		}
+	}
+}
+
+func TestLogger_SetSuccessField(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetJSON(true)
+	log.SetSuccessField("ok")
+
+	ok := func() interface{} {
+		t.Helper()
+		var obj map[string]interface{}
+		if err := json.Unmarshal(buf.Bytes(), &obj); err != nil {
+			t.Fatal(err)
+		}
+		buf.Reset()
+		return obj["ok"]
+	}
+
+	log.Info("served")
+	if got := ok(); got != true {
+		t.Fatalf("expected ok=true for Info, got %v", got)
+	}
+	log.Error("failed")
+	if got := ok(); got != false {
+		t.Fatalf("expected ok=false for Error, got %v", got)
+	}
+	log.Warn("slow")
+	if got := ok(); got != false {
+		t.Fatalf("expected ok=false for Warn by default, got %v", got)
+	}
+
+	log.SetSuccessThreshold(Error)
+	log.Warn("slow")
+	if got := ok(); got != true {
+		t.Fatalf("expected ok=true for Warn with an Error threshold, got %v", got)
+	}
+
+	log.SetSuccessField("")
+	log.Error("off")
+	if got := ok(); got != nil {
+		t.Fatalf("expected no success field when disabled, got %v", got)
+	}
+}
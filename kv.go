@@ -0,0 +1,43 @@
+package gologs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// KV 以 level 输出对齐的两列键值表, 键按字典序排列并右补齐到最长键的宽度,
+// 多行的值在后续行缩进到值所在列. 每一行作为一条独立的日志输出
+func (log *Logger) KV(level LogLevel, pairs map[string]interface{}) {
+	if !log.root().levelEnabled(level) {
+		return
+	}
+	for _, row := range kvRows(pairs) {
+		log.logInterface(nil, level, nil, row)
+	}
+}
+
+func kvRows(pairs map[string]interface{}) []string {
+	keys := make([]string, 0, len(pairs))
+	width := 0
+	for k := range pairs {
+		keys = append(keys, k)
+		if n := utf8.RuneCountInString(k); n > width {
+			width = n
+		}
+	}
+	sort.Strings(keys)
+
+	indent := strings.Repeat(" ", width+2)
+	rows := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pad := strings.Repeat(" ", width-utf8.RuneCountInString(k))
+		lines := strings.Split(fmt.Sprint(pairs[k]), "\n")
+		rows = append(rows, k+pad+": "+lines[0])
+		for _, line := range lines[1:] {
+			rows = append(rows, indent+line)
+		}
+	}
+	return rows
+}
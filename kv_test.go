@@ -0,0 +1,44 @@
+package gologs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLogger_KV(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetFormatter(map[LogLevel]string{Info: "%s\n"})
+
+	log.KV(Info, map[string]interface{}{
+		"timeout": "30s",
+		"host":    "localhost",
+		"id":      7,
+		"banner":  "hello\nworld",
+	})
+
+	want := strings.Join([]string{
+		"banner : hello",
+		"         world",
+		"host   : localhost",
+		"id     : 7",
+		"timeout: 30s",
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected table:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestLogger_KVFiltered(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Warn)
+	log.SetOutput(&buf)
+
+	log.KV(Info, map[string]interface{}{"a": 1})
+	if buf.Len() != 0 {
+		t.Fatalf("expected KV below level to be dropped, got %q", buf.String())
+	}
+}
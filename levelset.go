@@ -0,0 +1,63 @@
+package gologs
+
+// EnableLevel 显式开启 level 的输出, 不受 SetLevel 门限限制. 与 DisableLevel 配合可以挑选任意等级组合,
+// 例如门限为 Error 时开启 Debug, 适用于不按数值大小排序的自定义等级. Quiet 与 SetLevelFilter 仍然生效
+func (log *Logger) EnableLevel(level LogLevel) {
+	log.setLevelToggle(level, true)
+}
+
+// DisableLevel 显式关闭 level 的输出, 即使 level 达到 SetLevel 门限也不输出
+func (log *Logger) DisableLevel(level LogLevel) {
+	log.setLevelToggle(level, false)
+}
+
+// ResetLevelToggles 清除 EnableLevel/DisableLevel 的设置, 恢复只按门限判断
+func (log *Logger) ResetLevelToggles() {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.levelToggles = nil
+}
+
+func (log *Logger) setLevelToggle(level LogLevel, on bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	if log.levelToggles == nil {
+		log.levelToggles = make(map[LogLevel]bool)
+	}
+	log.levelToggles[level] = on
+}
+
+// SetLevelFilter 设置等级过滤函数, 只有 filter 返回 true 的等级才输出. 判断顺序为:
+// 先按 EnableLevel/DisableLevel 的显式开关, 没有开关的等级按 Level 门限, 通过后再经过 filter.
+// 因此 filter 只能进一步收紧输出, 例如门限为 Debug 时只保留 Hint 与 Important. 为 nil 时不过滤 (默认)
+func (log *Logger) SetLevelFilter(filter func(LogLevel) bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.levelFilter = filter
+}
+
+// EnableOnly 只输出 levels 中的等级, 是 SetLevelFilter 的便捷形式, 与 Level 门限的关系相同.
+// 不传参数时清除过滤
+func (log *Logger) EnableOnly(levels ...LogLevel) {
+	if len(levels) == 0 {
+		log.SetLevelFilter(nil)
+		return
+	}
+	allowed := make(map[LogLevel]bool, len(levels))
+	for _, level := range levels {
+		allowed[level] = true
+	}
+	log.SetLevelFilter(func(level LogLevel) bool { return allowed[level] })
+}
+
+// levelAllowed 判断 level 是否通过门限、显式开关与过滤函数, 调用方需持有读锁
+func (log *Logger) levelAllowed(level LogLevel) bool {
+	if on, ok := log.levelToggles[level]; ok {
+		if !on {
+			return false
+		}
+	} else if level < log.Level {
+		return false
+	}
+	return log.levelFilter == nil || log.levelFilter(level)
+}
@@ -0,0 +1,78 @@
+package gologs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLogger_EnableDisableLevel(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetFormatter(map[LogLevel]string{Info: "[-] %s\n"})
+
+	log.DisableLevel(Info)
+	log.Debug("debug")
+	log.Info("info")
+	log.Error("error")
+	if got := buf.String(); got != "[Debug] debug \n[Error] error \n" {
+		t.Fatalf("expected info to be suppressed, got %q", got)
+	}
+
+	buf.Reset()
+	log.SetLevel(Error)
+	log.EnableLevel(Debug)
+	log.Debug("debug")
+	log.Warn("warn")
+	log.Error("error")
+	if got := buf.String(); got != "[Debug] debug \n[Error] error \n" {
+		t.Fatalf("expected debug to bypass the threshold, got %q", got)
+	}
+
+	buf.Reset()
+	log.ResetLevelToggles()
+	log.SetLevel(Debug)
+	log.Info("info")
+	if got := buf.String(); got != "[-] info\n" {
+		t.Fatalf("expected threshold-only filtering after reset, got %q", got)
+	}
+}
+
+func TestLogger_EnableOnly(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetFormatter(map[LogLevel]string{Hint: "[H] %s\n", Important: "[I] %s\n"})
+
+	log.EnableOnly(Hint, Important)
+	log.Debug("debug")
+	log.Hint("hint")
+	log.Warn("warn")
+	log.Important("important")
+	log.Error("error")
+	if got := buf.String(); got != "[H] hint\n[I] important\n" {
+		t.Fatalf("expected only hint and important, got %q", got)
+	}
+
+	buf.Reset()
+	log.SetLevel(Important)
+	log.Hint("below threshold")
+	if buf.Len() != 0 {
+		t.Fatalf("expected the threshold to still apply, got %q", buf.String())
+	}
+
+	log.SetLevel(Debug)
+	log.SetLevelFilter(func(level LogLevel) bool { return level != Warn })
+	log.Warn("warn")
+	log.Error("error")
+	if got := buf.String(); got != "[Error] error \n" {
+		t.Fatalf("expected the custom filter to drop warn, got %q", got)
+	}
+
+	buf.Reset()
+	log.EnableOnly()
+	log.Warn("warn")
+	if got := buf.String(); got != "[Warn] warn \n" {
+		t.Fatalf("expected no filtering after EnableOnly(), got %q", got)
+	}
+}
@@ -0,0 +1,32 @@
+package gologs
+
+import (
+	"io"
+	"strings"
+)
+
+// flusher 是带缓冲的 writer (如 *bufio.Writer) 实现的接口
+type flusher interface {
+	Flush() error
+}
+
+// SetLineBuffered 开启后每写出一行以换行结尾的日志, 若 writer (包括 sink) 实现了 Flush() error
+// (如 *bufio.Writer), 立即调用 Flush, 日志即时可见; 不以换行结尾的片段仍留在缓冲中与后续内容合并写出
+func (log *Logger) SetLineBuffered(b bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.lineBuffered = b
+}
+
+// writeLine 写出 s, flush 为 true 且 s 以换行结尾时刷新带缓冲的 writer
+func writeLine(w io.Writer, s string, flush bool) {
+	writeString(w, s)
+	if !flush || !strings.HasSuffix(s, "\n") {
+		return
+	}
+	if f, ok := w.(flusher); ok {
+		locked := lockGlobalWrite()
+		defer unlockGlobalWrite(locked)
+		f.Flush()
+	}
+}
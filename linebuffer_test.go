@@ -0,0 +1,35 @@
+package gologs
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+)
+
+func TestLogger_SetLineBuffered(t *testing.T) {
+	var out bytes.Buffer
+	w := bufio.NewWriterSize(&out, 4096)
+	log := NewLogger(Debug)
+	log.SetOutput(w)
+
+	log.Warn("held")
+	if out.Len() != 0 {
+		t.Fatalf("expected line to stay in the buffer, got %q", out.String())
+	}
+
+	log.SetLineBuffered(true)
+	log.Warn("visible")
+	if got := out.String(); got != "[Warn] held \n[Warn] visible \n" {
+		t.Fatalf("expected buffer flushed after the line, got %q", got)
+	}
+
+	// 不以换行结尾的片段继续留在缓冲中
+	log.Console("partial ")
+	if got := out.String(); got != "[Warn] held \n[Warn] visible \n" {
+		t.Fatalf("expected partial write to stay buffered, got %q", got)
+	}
+	log.Warn("done")
+	if got := out.String(); got != "[Warn] held \n[Warn] visible \npartial [Warn] done \n" {
+		t.Fatalf("expected partial write flushed with the next line, got %q", got)
+	}
+}
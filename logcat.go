@@ -0,0 +1,36 @@
+package gologs
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+// SetLogcatFormat 将文本格式设置为 Android logcat 风格 "<priority>/<tag>(<pid>): <message>",
+// 等级映射为 logcat 优先级 V/D/I/W/E/F. 只覆盖调用时已登记的等级, 之后 AddLevel 增加的等级仍使用默认格式
+func (log *Logger) SetLogcatFormat(tag string) {
+	prefix := "/" + strings.ReplaceAll(tag, "%", "%%") + "(" + strconv.Itoa(os.Getpid()) + "): %s\n"
+	formatter := make(map[LogLevel]string, len(log.levels))
+	for level := range log.levels {
+		formatter[level] = logcatPriority(level) + prefix
+	}
+	log.SetFormatter(formatter)
+}
+
+// logcatPriority 返回 level 对应的 logcat 优先级字母
+func logcatPriority(level LogLevel) string {
+	switch {
+	case level < Debug:
+		return "V"
+	case level < Info:
+		return "D"
+	case level < Warn:
+		return "I"
+	case level < Error:
+		return "W"
+	case level < Fatal:
+		return "E"
+	default:
+		return "F"
+	}
+}
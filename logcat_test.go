@@ -0,0 +1,29 @@
+package gologs
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"testing"
+)
+
+func TestLogger_SetLogcatFormat(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetLogcatFormat("MyApp")
+	pid := strconv.Itoa(os.Getpid())
+
+	log.Debug("starting")
+	log.Hint("ready")
+	log.Warnf("slow %dms", 120)
+	log.Error("crashed")
+
+	want := "D/MyApp(" + pid + "): starting\n" +
+		"I/MyApp(" + pid + "): ready\n" +
+		"W/MyApp(" + pid + "): slow 120ms\n" +
+		"E/MyApp(" + pid + "): crashed\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected logcat output:\n%q\nwant:\n%q", got, want)
+	}
+}
@@ -0,0 +1,77 @@
+package gologs
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// SetLokiLabels 指定作为 Grafana Loki 流标签的字段. 这些字段从普通字段中取出, 文本模式下以 {key="value",...}
+// 标签块输出在行首 (模板包含 {{labels}} 时填入该位置), JSON 模式下放入 labels 对象, 其余字段仍留在日志行中.
+// 标签按传入顺序输出, 不传参数时关闭
+func (log *Logger) SetLokiLabels(keys ...string) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.lokiLabels = append([]string(nil), keys...)
+}
+
+// splitLokiLabels 将 fields 拆分为标签与其余字段, 标签按 SetLokiLabels 的顺序排列
+func (log *Logger) splitLokiLabels(fields []Field) (labels, rest []Field) {
+	if len(log.lokiLabels) == 0 {
+		return nil, fields
+	}
+	for _, key := range log.lokiLabels {
+		for _, f := range fields {
+			if f.Key == key {
+				labels = append(labels, f)
+				break
+			}
+		}
+	}
+	if len(labels) == 0 {
+		return nil, fields
+	}
+	for _, f := range fields {
+		if !containsString(log.lokiLabels, f.Key) {
+			rest = append(rest, f)
+		}
+	}
+	return labels, rest
+}
+
+// lokiLabelText 将标签渲染为 Loki 流选择器形式 {key="value",...}
+func lokiLabelText(labels []Field) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteByte('{')
+	for i, f := range labels {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(f.Key)
+		b.WriteByte('=')
+		b.WriteString(strconv.Quote(fmt.Sprint(f.Value)))
+	}
+	b.WriteByte('}')
+	return b.String()
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// lokiLabelMap 返回 JSON 输出使用的标签对象, 标签值统一为字符串
+func lokiLabelMap(labels []Field) map[string]string {
+	m := make(map[string]string, len(labels))
+	for _, f := range labels {
+		m[f.Key] = fmt.Sprint(f.Value)
+	}
+	return m
+}
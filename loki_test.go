@@ -0,0 +1,37 @@
+package gologs
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestLogger_SetLokiLabels(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetLokiLabels("service", "env")
+	svc := log.With(map[string]interface{}{"env": "prod", "service": "api", "user": "bob"})
+
+	svc.Warn("login")
+	if got := buf.String(); got != `{service="api",env="prod"} [Warn] login user=bob `+"\n" {
+		t.Fatalf("expected label block and remaining fields in the line, got %q", got)
+	}
+
+	buf.Reset()
+	log.SetFormatter(map[LogLevel]string{Warn: "[Warn] %s {{labels}}\n"})
+	svc.Warn("placed")
+	if got := buf.String(); got != `[Warn] placed user=bob {service="api",env="prod"}`+"\n" {
+		t.Fatalf("expected labels at {{labels}}, got %q", got)
+	}
+
+	buf.Reset()
+	log.SetJSON(true)
+	log.SetJSONSortKeys(true)
+	log.SetClock(func() time.Time { return time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC) })
+	svc.Warn("json")
+	want := `{"labels":{"env":"prod","service":"api"},"level":"Warn","message":"json","time":"2024-01-01T00:00:00Z","user":"bob"}` + "\n"
+	if got := buf.String(); got != want {
+		t.Fatalf("expected labels object in json, got %q", got)
+	}
+}
@@ -0,0 +1,142 @@
+package gologs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+// ErrMmapUnsupported 在不支持内存映射的平台调用 SetMmapFile 时返回
+var ErrMmapUnsupported = errors.New("gologs: memory-mapped log files are not supported on this platform")
+
+// mmapSyncInterval 为内存映射文件两次落盘之间的最短间隔
+const mmapSyncInterval = time.Second
+
+// SetMmapFile 增加一个写入内存映射文件的输出: 日志直接复制到映射区域, 不再每行一次 write 系统调用,
+// 适合极高频率的日志. 日志追加在 path 已有内容之后, size 为预留的映射大小, 空间不足时再扩展 size.
+// 距上次落盘超过 mmapSyncInterval 时同步到磁盘, Close 时同步并将文件截断为实际内容长度.
+// 输出格式与日志文件相同且不带颜色. 不支持的平台返回 ErrMmapUnsupported
+func (log *Logger) SetMmapFile(path string, size int64) error {
+	m, err := openMmapFile(path, size)
+	if err != nil {
+		return err
+	}
+
+	log.mu.Lock()
+	old := log.mmapFile
+	log.mmapFile = m
+	log.mu.Unlock()
+
+	if old != nil {
+		return old.Close()
+	}
+	return nil
+}
+
+// mmapWriter 将内存映射文件适配为 sink 使用的 io.Writer, 写入错误在释放锁之后交给错误处理函数
+type mmapWriter struct {
+	log *Logger
+}
+
+func (w mmapWriter) Write(p []byte) (int, error) {
+	if err := w.log.mmapFile.Write(p); err != nil {
+		w.log.deferError(fmt.Errorf("writing to mmap file: %w", err))
+	}
+	return len(p), nil
+}
+
+// mmapFile 是以共享内存映射方式写入的文件, off 之前为已写入的内容
+type mmapFile struct {
+	mu       sync.Mutex
+	file     *os.File
+	data     []byte
+	off      int
+	grow     int
+	lastSync time.Time
+}
+
+func openMmapFile(path string, size int64) (*mmapFile, error) {
+	if !mmapSupported {
+		return nil, ErrMmapUnsupported
+	}
+	if size <= 0 {
+		return nil, errors.New("gologs: mmap size must be positive")
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0666)
+	if err != nil {
+		return nil, err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	m := &mmapFile{file: file, off: int(info.Size()), grow: int(size), lastSync: time.Now()}
+	if err := m.remap(m.off + m.grow); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return m, nil
+}
+
+func (m *mmapFile) Write(p []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.data == nil {
+		return os.ErrClosed
+	}
+	if m.off+len(p) > len(m.data) {
+		if err := m.remap(m.off + len(p) + m.grow); err != nil {
+			return err
+		}
+	}
+	m.off += copy(m.data[m.off:], p)
+
+	if time.Since(m.lastSync) >= mmapSyncInterval {
+		m.lastSync = time.Now()
+		return m.file.Sync()
+	}
+	return nil
+}
+
+// remap 将文件扩展到 size 并重新映射, 调用方需持有 mu
+func (m *mmapFile) remap(size int) error {
+	if m.data != nil {
+		if err := munmapRegion(m.data); err != nil {
+			return err
+		}
+		m.data = nil
+	}
+	if err := m.file.Truncate(int64(size)); err != nil {
+		return err
+	}
+	data, err := mmapRegion(m.file, size)
+	if err != nil {
+		return err
+	}
+	m.data = data
+	return nil
+}
+
+// Close 解除映射, 将文件截断为实际写入的长度并落盘
+func (m *mmapFile) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.data == nil {
+		return nil
+	}
+	err := munmapRegion(m.data)
+	m.data = nil
+	if terr := m.file.Truncate(int64(m.off)); err == nil {
+		err = terr
+	}
+	if serr := m.file.Sync(); err == nil {
+		err = serr
+	}
+	if cerr := m.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
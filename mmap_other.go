@@ -0,0 +1,15 @@
+//go:build !unix
+
+package gologs
+
+import "os"
+
+const mmapSupported = false
+
+func mmapRegion(f *os.File, size int) ([]byte, error) {
+	return nil, ErrMmapUnsupported
+}
+
+func munmapRegion(data []byte) error {
+	return ErrMmapUnsupported
+}
@@ -0,0 +1,76 @@
+//go:build unix
+
+package gologs
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogger_SetMmapFile(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "trace.log")
+	if err := os.WriteFile(filename, []byte("[Warn] earlier \n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	log := NewLogger(Debug)
+	log.SetOutput(io.Discard)
+	log.SetColor(true)
+	// 映射区域很小, 写入过程中需要多次扩展
+	if err := log.SetMmapFile(filename, 32); err != nil {
+		t.Fatal(err)
+	}
+
+	var want strings.Builder
+	want.WriteString("[Warn] earlier \n")
+	for i := 0; i < 50; i++ {
+		log.Warnf("event %d", i)
+		fmt.Fprintf(&want, "[Warn] event %d \n", i)
+	}
+	log.Close()
+
+	got, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want.String() {
+		t.Fatalf("unexpected mmap file content:\n%q\nwant:\n%q", got, want.String())
+	}
+}
+
+func BenchmarkMmapFile(b *testing.B) {
+	log := NewLogger(Debug)
+	log.SetOutput(io.Discard)
+	if err := log.SetMmapFile(filepath.Join(b.TempDir(), "bench.log"), 64<<20); err != nil {
+		b.Fatal(err)
+	}
+	defer log.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.Warn("benchmark line with a bit of payload")
+	}
+}
+
+func BenchmarkBufferedFile(b *testing.B) {
+	f, err := os.Create(filepath.Join(b.TempDir(), "bench.log"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	defer w.Flush()
+
+	log := NewLogger(Debug)
+	log.SetOutput(w)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		log.Warn("benchmark line with a bit of payload")
+	}
+}
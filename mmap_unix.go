@@ -0,0 +1,18 @@
+//go:build unix
+
+package gologs
+
+import (
+	"os"
+	"syscall"
+)
+
+const mmapSupported = true
+
+func mmapRegion(f *os.File, size int) ([]byte, error) {
+	return syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+}
+
+func munmapRegion(data []byte) error {
+	return syscall.Munmap(data)
+}
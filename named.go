@@ -0,0 +1,48 @@
+package gologs
+
+// componentKey 为 Named 设置的组件名字段, 文本模板中可通过 {{component}} 指定位置
+const componentKey = "component"
+
+// Named 返回名为 name 的子 Logger, 嵌套调用时名称以 "." 连接 (例如 db.pool).
+// 组件名在 JSON 中输出为 component 字段, 文本模式下填入 {{component}}, 模板中没有该占位符时以 component=... 追加.
+// 子 Logger 共用根 Logger 的配置和输出, 只维护自己的名称, 修改配置请调用根 Logger 的方法
+func (log *Logger) Named(name string) *Logger {
+	if log.name != "" {
+		name = log.name + "." + name
+	}
+	return &Logger{
+		parent: log.root(),
+		name:   name,
+		fields: log.limitFields(mergeFields(log.fields, []Field{{Key: componentKey, Value: name}})),
+	}
+}
+
+// With 返回携带 fields 的子 Logger, 之后的每条日志都附带这些字段, 同名字段以新值为准.
+// 字段在所有 sink 中输出: JSON 中为对象成员, 文本中以 key=value 追加.
+// 与 Named 相同, 子 Logger 共用根 Logger 的配置和输出
+func (log *Logger) With(fields map[string]interface{}) *Logger {
+	return &Logger{
+		parent: log.root(),
+		name:   log.name,
+		fields: log.limitFields(mergeFields(log.fields, fieldsFromMap(fields))),
+	}
+}
+
+// root 返回实际负责配置与输出的根 Logger
+func (log *Logger) root() *Logger {
+	if log.parent != nil {
+		return log.parent
+	}
+	return log
+}
+
+// withFields 返回子 Logger 携带的字段与本次调用字段合并后的结果
+func (log *Logger) withFields(fields []Field) []Field {
+	if len(log.fields) == 0 {
+		return fields
+	}
+	if len(fields) == 0 {
+		return log.fields
+	}
+	return mergeFields(log.fields, fields)
+}
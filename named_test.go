@@ -0,0 +1,103 @@
+package gologs
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLogger_Named(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+
+	pool := log.Named("db").Named("pool")
+	pool.Warn("exhausted")
+	if got := buf.String(); got != "[Warn] exhausted component=db.pool \n" {
+		t.Fatalf("unexpected text output: %q", got)
+	}
+
+	buf.Reset()
+	log.SetFormatter(map[LogLevel]string{Warn: "[Warn] ({{component}}) %s\n"})
+	pool.Warn("exhausted")
+	log.Warn("root")
+	if got := buf.String(); got != "[Warn] (db.pool) exhausted\n[Warn] () root\n" {
+		t.Fatalf("unexpected {{component}} output: %q", got)
+	}
+
+	buf.Reset()
+	log.SetJSON(true)
+	log.Named("http").Warn("slow")
+	if err := ValidateJSONOutput(buf.String(), "component:string"); err != nil {
+		t.Fatalf("%v: %q", err, buf.String())
+	}
+
+	// 子 Logger 使用根 Logger 的等级
+	buf.Reset()
+	log.SetLevel(Error)
+	pool.Warn("filtered")
+	if buf.Len() != 0 {
+		t.Fatalf("expected child to follow root level, got %q", buf.String())
+	}
+}
+
+func TestLogger_WithFieldsAcrossSinks(t *testing.T) {
+	var stdout, file bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&stdout)
+	log.SetJSON(true)
+	log.AddSink(Sink{Writer: &file, Formatter: TextFormatter{}})
+
+	req := log.With(map[string]interface{}{"request_id": "r1"})
+	req.Warn("done")
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(stdout.Bytes(), &obj); err != nil {
+		t.Fatalf("expected json on stdout, got %q: %v", stdout.String(), err)
+	}
+	if obj["request_id"] != "r1" || obj["message"] != "done" {
+		t.Fatalf("expected request_id field in json, got %v", obj)
+	}
+	if got := file.String(); got != "[Warn] done request_id=r1 \n" {
+		t.Fatalf("expected request_id in text sink, got %q", got)
+	}
+
+	// 子 Logger 的字段不影响父 Logger
+	stdout.Reset()
+	log.Warn("plain")
+	if strings.Contains(stdout.String(), "request_id") {
+		t.Fatalf("fields leaked to parent: %q", stdout.String())
+	}
+}
+
+func TestLogger_NamedSharesRootState(t *testing.T) {
+	log := newBrokenFileLogger(t)
+	log.SetErrorHandler(func(error) {})
+	log.SetSkipNilError(true)
+	log.SetErrorBufferSize(2)
+	log.SetMaxTotalBytes(60)
+	child := log.Named("db")
+
+	child.Error(nil)
+	if got := log.RecentErrors(); len(got) != 0 {
+		t.Fatalf("expected child Error(nil) to follow root SetSkipNilError, got %v", got)
+	}
+
+	child.Error("boom")
+	if got := child.RecentErrors(); len(got) != 1 || got[0].Message != "boom" {
+		t.Fatalf("expected child to see the root error buffer, got %v", got)
+	}
+	if child.LastError() == nil || child.LastWriteError() == nil {
+		t.Fatal("expected child to report the root write error")
+	}
+	child.ClearLastError()
+	if log.LastError() != nil {
+		t.Fatal("expected child ClearLastError to clear the root error")
+	}
+
+	child.Error("over budget")
+	if got := child.BudgetDropped(); got != 1 {
+		t.Fatalf("expected child to report root budget drops, got %d", got)
+	}
+}
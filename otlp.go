@@ -0,0 +1,235 @@
+package gologs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// otlpQueueSize 为等待导出的日志记录上限, 队列满时丢弃新记录, 不阻塞日志调用
+	otlpQueueSize = 1024
+	// otlpBatchSize 为单次请求最多携带的日志记录数
+	otlpBatchSize = 100
+	// otlpScopeName 为导出记录的 InstrumentationScope 名称
+	otlpScopeName = "github.com/coutcin-xw/gologs"
+)
+
+// otlpExporter 将日志记录以 OTLP/HTTP JSON 编码批量发送到 endpoint, 由单个后台 goroutine 按顺序发送
+type otlpExporter struct {
+	endpoint string
+	client   *http.Client
+	ch       chan otlpLogRecord
+	done     chan struct{}
+}
+
+// SetOTLPOutput 开启 OpenTelemetry 日志导出: 每条输出的日志转换为 OTLP LogRecord (severity 由等级映射,
+// body 为消息, 结构化字段与调用位置为 attributes), 由后台 goroutine 以 OTLP/HTTP JSON 编码批量 POST 到 endpoint,
+// endpoint 为完整地址, 例如 "http://localhost:4318/v1/logs". 发送失败交给错误处理函数, 队列满时丢弃新记录.
+// 传入空字符串时发送完剩余记录后关闭导出, Close 时同样会发送完剩余记录
+func (log *Logger) SetOTLPOutput(endpoint string) {
+	log.otlpMu.Lock()
+	old := log.detachOTLP()
+	if endpoint == "" {
+		log.otlpMu.Unlock()
+		old.wait()
+		return
+	}
+
+	x := &otlpExporter{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		ch:       make(chan otlpLogRecord, otlpQueueSize),
+		done:     make(chan struct{}),
+	}
+	log.otlp = x
+	log.otlpMu.Unlock()
+	old.wait()
+	go func() {
+		defer close(x.done)
+		for rec := range x.ch {
+			batch := []otlpLogRecord{rec}
+		drain:
+			for len(batch) < otlpBatchSize {
+				select {
+				case rec, ok := <-x.ch:
+					if !ok {
+						break drain
+					}
+					batch = append(batch, rec)
+				default:
+					break drain
+				}
+			}
+			if err := x.send(batch); err != nil {
+				log.handleError(fmt.Errorf("exporting otlp logs: %w", err))
+			}
+		}
+	}()
+}
+
+// StopOTLP 发送完剩余记录并关闭 OTLP 导出. Close 时会自动调用
+func (log *Logger) StopOTLP() {
+	log.otlpMu.Lock()
+	x := log.detachOTLP()
+	log.otlpMu.Unlock()
+	x.wait()
+}
+
+// detachOTLP 摘下当前的导出器并关闭其队列, 调用方需持有 otlpMu 写锁.
+// 发送失败时错误处理函数可能再次写日志而进入 exportOTLP, 因此不能在持有 otlpMu 时等待发送完成
+func (log *Logger) detachOTLP() *otlpExporter {
+	x := log.otlp
+	if x == nil {
+		return nil
+	}
+	log.otlp = nil
+	close(x.ch)
+	return x
+}
+
+// wait 等待后台 goroutine 发送完剩余记录并退出, x 为 nil 时直接返回
+func (x *otlpExporter) wait() {
+	if x == nil {
+		return
+	}
+	<-x.done
+}
+
+// exportOTLP 将 e 放入导出队列, 未开启导出时直接返回
+func (log *Logger) exportOTLP(e *LogEntry) {
+	log.otlpMu.RLock()
+	defer log.otlpMu.RUnlock()
+	if log.otlp == nil {
+		return
+	}
+	select {
+	case log.otlp.ch <- log.otlpRecord(e):
+	default:
+	}
+}
+
+func (x *otlpExporter) send(batch []otlpLogRecord) error {
+	body, err := json.Marshal(otlpLogsRequest{ResourceLogs: []otlpResourceLogs{{
+		ScopeLogs: []otlpScopeLogs{{Scope: otlpScope{Name: otlpScopeName}, LogRecords: batch}},
+	}}})
+	if err != nil {
+		return err
+	}
+	resp, err := x.client.Post(x.endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+// otlpSeverity 将 LogLevel 映射为 OTel SeverityNumber: DEBUG=5, INFO=9~11, WARN=13, ERROR=17, FATAL=21
+func otlpSeverity(level LogLevel) int {
+	switch {
+	case level < Info:
+		return 5
+	case level < Hint:
+		return 9
+	case level < Important:
+		return 10
+	case level < Warn:
+		return 11
+	case level < Error:
+		return 13
+	case level < Fatal:
+		return 17
+	default:
+		return 21
+	}
+}
+
+// otlpRecord 将日志条目转换为 OTLP LogRecord, 调用方需持有读锁
+func (log *Logger) otlpRecord(e *LogEntry) otlpLogRecord {
+	rec := otlpLogRecord{
+		TimeUnixNano:   strconv.FormatInt(e.Time.UnixNano(), 10),
+		SeverityNumber: otlpSeverity(e.Level),
+		SeverityText:   log.levelName(e.Level),
+		Body:           otlpValue{StringValue: &e.Message},
+	}
+	for _, f := range e.Fields {
+		rec.Attributes = append(rec.Attributes, otlpAttr(f.Key, f.Value))
+	}
+	if c := e.Caller; c != nil {
+		rec.Attributes = append(rec.Attributes,
+			otlpAttr("code.filepath", c.File),
+			otlpAttr("code.lineno", c.Line),
+			otlpAttr("code.function", c.Function))
+	}
+	return rec
+}
+
+// otlpAttr 按值的类型选择 AnyValue 的字段, 其他类型以 fmt.Sprint 的文本输出
+func otlpAttr(key string, v interface{}) otlpKeyValue {
+	var val otlpValue
+	switch v := v.(type) {
+	case string:
+		val.StringValue = &v
+	case bool:
+		val.BoolValue = &v
+	case int:
+		s := strconv.Itoa(v)
+		val.IntValue = &s
+	case int64:
+		s := strconv.FormatInt(v, 10)
+		val.IntValue = &s
+	case float64:
+		val.DoubleValue = &v
+	default:
+		s := fmt.Sprint(v)
+		val.StringValue = &s
+	}
+	return otlpKeyValue{Key: key, Value: val}
+}
+
+// 以下类型对应 OTLP/HTTP JSON 编码的 ExportLogsServiceRequest, 只包含用到的字段
+type otlpLogsRequest struct {
+	ResourceLogs []otlpResourceLogs `json:"resourceLogs"`
+}
+
+type otlpResourceLogs struct {
+	ScopeLogs []otlpScopeLogs `json:"scopeLogs"`
+}
+
+type otlpScopeLogs struct {
+	Scope      otlpScope       `json:"scope"`
+	LogRecords []otlpLogRecord `json:"logRecords"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpLogRecord struct {
+	TimeUnixNano   string         `json:"timeUnixNano"`
+	SeverityNumber int            `json:"severityNumber"`
+	SeverityText   string         `json:"severityText"`
+	Body           otlpValue      `json:"body"`
+	Attributes     []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+// otlpValue 对应 AnyValue, int64 按 proto3 JSON 的约定编码为字符串
+type otlpValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
@@ -0,0 +1,107 @@
+package gologs
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLogger_SetOTLPOutput(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		records []otlpLogRecord
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/logs" || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("unexpected request %s %s", r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		var req otlpLogsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		mu.Lock()
+		for _, rl := range req.ResourceLogs {
+			for _, sl := range rl.ScopeLogs {
+				records = append(records, sl.LogRecords...)
+			}
+		}
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	log := NewLogger(Info)
+	log.SetOutput(io.Discard)
+	log.SetOTLPOutput(srv.URL + "/v1/logs")
+	log.Debug("filtered")
+	log.InfoFields("started", map[string]interface{}{"port": 8080, "tls": true})
+	log.Error("boom")
+	log.Close()
+
+	if len(records) != 2 {
+		t.Fatalf("expected 2 exported records, got %+v", records)
+	}
+	info, errRec := records[0], records[1]
+	if info.SeverityNumber != 9 || info.SeverityText != "Info" || *info.Body.StringValue != "started" {
+		t.Fatalf("unexpected info record %+v", info)
+	}
+	if len(info.Attributes) != 2 || info.Attributes[0].Key != "port" || *info.Attributes[0].Value.IntValue != "8080" ||
+		info.Attributes[1].Key != "tls" || !*info.Attributes[1].Value.BoolValue {
+		t.Fatalf("unexpected attributes %+v", info.Attributes)
+	}
+	if errRec.SeverityNumber != 17 || *errRec.Body.StringValue != "boom" || errRec.TimeUnixNano == "" {
+		t.Fatalf("unexpected error record %+v", errRec)
+	}
+}
+
+func TestLogger_OTLPExportError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	var reported []error
+	log := NewLogger(Info)
+	log.SetOutput(io.Discard)
+	log.SetErrorHandler(func(err error) { reported = append(reported, err) })
+	log.SetOTLPOutput(srv.URL)
+	log.Warn("lost")
+	log.StopOTLP()
+
+	if len(reported) != 1 {
+		t.Fatalf("expected the failed export to be reported, got %v", reported)
+	}
+}
+
+func TestLogger_OTLPExportErrorHandlerLogs(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	var buf syncBuffer
+	log := NewLogger(Info)
+	log.SetOutput(&buf)
+	// handler 自身写日志, 它在导出 goroutine 中执行, 写的日志会再次进入 exportOTLP
+	log.SetErrorHandler(func(err error) { log.Warnf("export failed: %v", err) })
+	log.SetOTLPOutput(srv.URL)
+	log.Warn("lost")
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		log.Close()
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Close to return when the error handler logs")
+	}
+	if !strings.Contains(buf.String(), "export failed") {
+		t.Fatalf("expected the handler's line in output, got %q", buf.String())
+	}
+}
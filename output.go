@@ -0,0 +1,73 @@
+package gologs
+
+import (
+	"io"
+	"sync"
+)
+
+// AddOutput 添加一个与 SetOutput 设置的 writer 并列的输出目的地. 终端 (TTY) 按 Color 设置输出带颜色的行,
+// 其他 writer 输出不带颜色的行 (SetColorForcePipe 开启时同样带颜色).
+// 只作用于默认输出, F* 系列显式指定 writer 的方法不受影响.
+// 存在附加输出时, 对包括 writer 在内的所有目的地的写入由同一把锁串行化
+func (log *Logger) AddOutput(w io.Writer) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	outputs := make([]addedOutput, len(log.outputs), len(log.outputs)+1)
+	copy(outputs, log.outputs)
+	log.outputs = append(outputs, addedOutput{w: w, tty: isTerminal(w)})
+}
+
+// RemoveOutput 移除通过 AddOutput 添加的 w, w 未添加时不做任何事. w 需为可比较的类型 (通常是指针)
+func (log *Logger) RemoveOutput(w io.Writer) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	outputs := make([]addedOutput, 0, len(log.outputs))
+	for _, o := range log.outputs {
+		if o.w != w {
+			outputs = append(outputs, o)
+		}
+	}
+	log.outputs = outputs
+}
+
+// addedOutput 是 AddOutput 添加的输出, tty 为添加时检测的 writer 是否为终端, 避免每行日志都检测一次
+type addedOutput struct {
+	w   io.Writer
+	tty bool
+}
+
+// outputSinks 返回默认输出的全部目的地: writer 在前, 之后是 AddOutput 添加的 writer
+func (log *Logger) outputSinks(writer io.Writer, color bool) []Sink {
+	if len(log.outputs) == 0 {
+		return []Sink{{Writer: writer, Color: color}}
+	}
+	sinks := make([]Sink, 0, len(log.outputs)+1)
+	sinks = append(sinks, Sink{Writer: lockedWriter{&log.outputMu, writer}, Color: color})
+	for _, o := range log.outputs {
+		sinks = append(sinks, Sink{Writer: lockedWriter{&log.outputMu, o.w}, Color: log.Color && (log.colorForcePipe || o.tty)})
+	}
+	return sinks
+}
+
+// lockedWriter 在持有 mu 时写入 w, 用于串行化多个输出目的地的写入
+type lockedWriter struct {
+	mu *sync.Mutex
+	w  io.Writer
+}
+
+func (lw lockedWriter) Write(p []byte) (int, error) {
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	return lw.w.Write(p)
+}
+
+// Flush 在 w 实现了 flusher 时转发, 使 SetLineBuffered 对附加输出同样生效
+func (lw lockedWriter) Flush() error {
+	f, ok := lw.w.(flusher)
+	if !ok {
+		return nil
+	}
+	lw.mu.Lock()
+	defer lw.mu.Unlock()
+	return f.Flush()
+}
@@ -0,0 +1,62 @@
+package gologs
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLogger_AddOutput(t *testing.T) {
+	var primary, extra bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&primary)
+	log.SetColor(true)
+	log.AddOutput(&extra)
+
+	log.Warn("both")
+	if got := extra.String(); got != "[Warn] both \n" {
+		t.Fatalf("expected plain line on non-tty output, got %q", got)
+	}
+	if got := primary.String(); got == "[Warn] both \n" || !strings.Contains(got, "both") {
+		t.Fatalf("expected colored line on the primary writer, got %q", got)
+	}
+
+	var explicit bytes.Buffer
+	extra.Reset()
+	log.FWarnf(&explicit, "%s", "explicit")
+	if extra.Len() != 0 || !strings.Contains(explicit.String(), "explicit") {
+		t.Fatalf("expected F* output to bypass added outputs, got %q / %q", explicit.String(), extra.String())
+	}
+
+	log.RemoveOutput(&extra)
+	log.Warn("removed")
+	if extra.Len() != 0 {
+		t.Fatalf("expected no output after RemoveOutput, got %q", extra.String())
+	}
+}
+
+func TestLogger_AddOutputConcurrent(t *testing.T) {
+	var primary, extra bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&primary)
+	log.AddOutput(&extra)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				log.Warn("line")
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, buf := range []*bytes.Buffer{&primary, &extra} {
+		if got := strings.Count(buf.String(), "[Warn] line \n"); got != 400 {
+			t.Fatalf("expected 400 intact lines, got %d", got)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package gologs
+
+import "io"
+
+// FlushOnPanic 供 main 中 defer 调用: 发生未恢复的 panic 时先写完 Log 的异步队列与 OTLP 导出、刷新带缓冲的输出并将日志文件落盘,
+// 再以原值重新 panic, 崩溃照常发生但最后的日志不会丢失. 没有 panic 时不做任何事
+//
+//	func main() {
+//		defer gologs.FlushOnPanic()
+//		...
+//	}
+func FlushOnPanic() {
+	if r := recover(); r != nil {
+		Log.flushAll()
+		panic(r)
+	}
+}
+
+// FlushOnPanic 与包级的 FlushOnPanic 相同, 作用于 log. 必须直接 defer 调用才能捕获 panic
+func (log *Logger) FlushOnPanic() {
+	if r := recover(); r != nil {
+		log.flushAll()
+		panic(r)
+	}
+}
+
+// flushAll 写完异步队列与 OTLP 导出, 刷新实现了 Flush 的输出 (包括附加输出与 sink), 并将日志文件落盘
+func (log *Logger) flushAll() {
+	root := log.root()
+	root.Flush()
+	root.StopOTLP()
+
+	root.mu.RLock()
+	writers := []io.Writer{root.writer, root.errorOutput}
+	for _, o := range root.outputs {
+		writers = append(writers, o.w)
+	}
+	for _, sink := range root.extSinks {
+		writers = append(writers, sink.Writer)
+	}
+	root.mu.RUnlock()
+
+	locked := lockGlobalWrite()
+	for _, w := range writers {
+		if f, ok := w.(flusher); ok {
+			f.Flush()
+		}
+	}
+	unlockGlobalWrite(locked)
+	root.Sync()
+}
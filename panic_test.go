@@ -0,0 +1,51 @@
+package gologs
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+)
+
+func TestLogger_FlushOnPanic(t *testing.T) {
+	var out, sinkOut bytes.Buffer
+	log := NewLogger(Debug)
+	w := bufio.NewWriterSize(&out, 4096)
+	log.SetOutput(w)
+	sw := bufio.NewWriterSize(&sinkOut, 4096)
+	log.AddSink(Sink{Writer: sw})
+	log.SetAsync(16)
+	defer log.StopAsync()
+
+	var recovered interface{}
+	func() {
+		defer func() { recovered = recover() }()
+		defer log.FlushOnPanic()
+		log.Warn("last words")
+		panic("crash")
+	}()
+
+	if recovered != "crash" {
+		t.Fatalf("expected the panic to propagate, got %v", recovered)
+	}
+	if got := out.String(); got != "[Warn] last words \n" {
+		t.Fatalf("expected buffered output to be flushed, got %q", got)
+	}
+	if got := sinkOut.String(); got != "[Warn] last words \n" {
+		t.Fatalf("expected sink output to be flushed, got %q", got)
+	}
+}
+
+func TestLogger_FlushOnPanicNoPanic(t *testing.T) {
+	var out bytes.Buffer
+	log := NewLogger(Debug)
+	w := bufio.NewWriter(&out)
+	log.SetOutput(w)
+
+	func() {
+		defer log.FlushOnPanic()
+		log.Warn("buffered")
+	}()
+	if out.Len() != 0 {
+		t.Fatalf("expected no flush without a panic, got %q", out.String())
+	}
+}
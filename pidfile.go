@@ -0,0 +1,31 @@
+package gologs
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// WritePIDFile 将当前进程 PID 写入 path, Close 时删除该文件. 常用于与日志文件配套的守护进程
+func (log *Logger) WritePIDFile(path string) error {
+	if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getpid())+"\n"), 0644); err != nil {
+		return err
+	}
+	log.mu.Lock()
+	log.pidFile = path
+	log.mu.Unlock()
+	return nil
+}
+
+// removePIDFile 删除 WritePIDFile 写入的文件, 调用方需持有写锁
+func (log *Logger) removePIDFile() error {
+	if log.pidFile == "" {
+		return nil
+	}
+	path := log.pidFile
+	log.pidFile = ""
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing pid file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,29 @@
+package gologs
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func TestLogger_WritePIDFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.pid")
+	log := NewLogger(Debug)
+
+	if err := log.WritePIDFile(path); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != strconv.Itoa(os.Getpid())+"\n" {
+		t.Fatalf("unexpected pid file content %q", got)
+	}
+
+	log.Close()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected pid file to be removed on close, got %v", err)
+	}
+}
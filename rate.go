@@ -0,0 +1,90 @@
+package gologs
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// rateSlot 是一秒内某个等级的日志计数, sec 为该槽位当前对应的 Unix 秒
+type rateSlot struct {
+	sec atomic.Int64
+	n   atomic.Int64
+}
+
+// rateCounter 以每秒一个槽位的环形缓冲统计各等级最近一段时间的日志条数
+type rateCounter struct {
+	mu     sync.RWMutex
+	window int64 // 窗口秒数, 0 表示未开启
+	rings  map[LogLevel][]rateSlot
+}
+
+// SetRateWindow 开启按等级统计日志速率, window 为统计窗口 (按秒取整, 至少 1 秒), 供 RatePerMinute 使用.
+// window <= 0 关闭统计 (默认). 调用时清空已有计数
+func (log *Logger) SetRateWindow(window time.Duration) {
+	r := &log.rates
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.window = 0
+	r.rings = nil
+	if window <= 0 {
+		return
+	}
+	r.window = int64(window / time.Second)
+	if r.window < 1 {
+		r.window = 1
+	}
+	r.rings = make(map[LogLevel][]rateSlot)
+}
+
+// RatePerMinute 返回窗口内各等级的日志速率 (条/分钟), 只包含窗口内有日志的等级. 未开启统计时返回空 map
+func (log *Logger) RatePerMinute() map[LogLevel]float64 {
+	r := &log.root().rates
+	now := log.root().clockNow().Unix()
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	rates := make(map[LogLevel]float64)
+	for level, ring := range r.rings {
+		var total int64
+		for i := range ring {
+			if sec := ring[i].sec.Load(); sec > now-r.window && sec <= now {
+				total += ring[i].n.Load()
+			}
+		}
+		if total > 0 {
+			rates[level] = float64(total) * 60 / float64(r.window)
+		}
+	}
+	return rates
+}
+
+// countRate 为 level 在当前秒的槽位计数. 槽位过期时重置, 并发重置与计数之间的竞争只会造成很小的误差
+func (log *Logger) countRate(level LogLevel) {
+	r := &log.rates
+	r.mu.RLock()
+	if r.window == 0 {
+		r.mu.RUnlock()
+		return
+	}
+	ring, ok := r.rings[level]
+	r.mu.RUnlock()
+	if !ok {
+		r.mu.Lock()
+		if ring, ok = r.rings[level]; !ok && r.window > 0 {
+			ring = make([]rateSlot, r.window)
+			r.rings[level] = ring
+		}
+		r.mu.Unlock()
+		if ring == nil {
+			return
+		}
+	}
+
+	sec := log.now().Unix()
+	slot := &ring[sec%int64(len(ring))]
+	if old := slot.sec.Load(); old != sec && slot.sec.CompareAndSwap(old, sec) {
+		slot.n.Store(0)
+	}
+	slot.n.Add(1)
+}
@@ -0,0 +1,81 @@
+package gologs
+
+import (
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLogger_RatePerMinute(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	var mu sync.Mutex
+	log := NewLogger(Debug)
+	log.SetOutput(io.Discard)
+	log.SetClock(func() time.Time {
+		mu.Lock()
+		defer mu.Unlock()
+		return now
+	})
+	advance := func(d time.Duration) {
+		mu.Lock()
+		now = now.Add(d)
+		mu.Unlock()
+	}
+
+	if rates := log.RatePerMinute(); len(rates) != 0 {
+		t.Fatalf("expected no rates before enabling, got %v", rates)
+	}
+	log.SetRateWindow(10 * time.Second)
+
+	// 10 秒内每秒 3 条 Warn, 第 5 秒额外 5 条 Error
+	for sec := 0; sec < 10; sec++ {
+		var wg sync.WaitGroup
+		for i := 0; i < 3; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				log.Warn("tick")
+			}()
+		}
+		wg.Wait()
+		if sec == 5 {
+			for i := 0; i < 5; i++ {
+				log.Error("burst")
+			}
+		}
+		advance(time.Second)
+	}
+	advance(-time.Second)
+
+	rates := log.RatePerMinute()
+	if got := rates[Warn]; got < 179 || got > 181 {
+		t.Fatalf("expected about 180 Warn/min, got %v", got)
+	}
+	if got := rates[Error]; got != 30 {
+		t.Fatalf("expected 30 Error/min, got %v", got)
+	}
+
+	// 窗口滑过之后旧的计数不再计入
+	advance(6 * time.Second)
+	if got := log.RatePerMinute()[Error]; got != 0 {
+		t.Fatalf("expected burst to leave the window, got %v", got)
+	}
+}
+
+func TestLogger_RatePerMinuteWithConcurrentSetClock(t *testing.T) {
+	log := NewLogger(Debug)
+	log.SetRateWindow(time.Minute)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			log.SetClock(time.Now)
+		}
+	}()
+	for i := 0; i < 200; i++ {
+		log.RatePerMinute()
+	}
+	<-done
+}
@@ -0,0 +1,50 @@
+package gologs
+
+import (
+	"fmt"
+	"os"
+	"time"
+)
+
+// reopenCheckInterval 为 SetReopenOnDelete 检查日志文件路径的间隔
+const reopenCheckInterval = time.Second
+
+// SetReopenOnDelete 开启后写日志文件时每隔 reopenCheckInterval 检查一次 LogFileName,
+// 发现文件被删除或被替换为其他文件时重新打开, 避免日志写入已删除的 inode 而不可见
+func (log *Logger) SetReopenOnDelete(b bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.reopenOnDelete = b
+}
+
+// reopenIfDeleted 在日志文件路径不再指向当前打开的文件时重新打开, 调用方需持有 muf
+func (log *Logger) reopenIfDeleted() error {
+	if !log.reopenOnDelete {
+		return nil
+	}
+	now := log.now()
+	if now.Sub(log.reopenCheckedAt) < reopenCheckInterval {
+		return nil
+	}
+	log.reopenCheckedAt = now
+
+	opened, err := log.logFile.Stat()
+	if err != nil {
+		return fmt.Errorf("checking logfile: %w", err)
+	}
+	if current, err := os.Stat(log.LogFileName); err == nil && os.SameFile(opened, current) {
+		return nil
+	}
+
+	file, err := os.OpenFile(log.LogFileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return fmt.Errorf("reopening logfile: %w", err)
+	}
+	log.logFile.Close()
+	log.logFile = file
+	log.fileSize = 0
+	if info, err := file.Stat(); err == nil {
+		log.fileSize = info.Size()
+	}
+	return nil
+}
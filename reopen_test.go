@@ -0,0 +1,39 @@
+package gologs
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLogger_SetReopenOnDelete(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "app.log")
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	log := NewLogger(Debug)
+	log.SetOutput(io.Discard)
+	log.SetClock(func() time.Time { return now })
+	log.SetFile(filename)
+	log.SetIsLogToFile(true)
+	log.SetReopenOnDelete(true)
+
+	log.Warn("before")
+	if err := os.Remove(filename); err != nil {
+		t.Fatal(err)
+	}
+	// 检查间隔内的写入仍落到已删除的文件
+	log.Warn("lost")
+	now = now.Add(reopenCheckInterval)
+	log.Warn("after")
+	log.Warn("again")
+	log.Close()
+
+	got, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("expected log file to be recreated: %v", err)
+	}
+	if string(got) != "[Warn] after \n[Warn] again \n" {
+		t.Fatalf("expected subsequent lines in the new file, got %q", got)
+	}
+}
@@ -0,0 +1,69 @@
+package gologs
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+)
+
+// replayDefaultLevel 是 Replay 无法识别等级标签时使用的等级
+const replayDefaultLevel = Info
+
+// Replay 逐行读取文本日志并通过当前 Logger 重新输出, 用于把旧日志转换为新的格式或写到新的 sink.
+// 行首的 "[Level]" 标签 (包括 [-]、[+]、[*] 等格式模板中的标签) 解析为等级, 行尾默认后缀 ", 2006-01-02 15:04.05"
+// 解析为日志时间并通过 LogAt 保留. 无法识别等级的行以 Info 等级原样输出, 空行被忽略
+func (log *Logger) Replay(r io.Reader) error {
+	tags := log.root().replayTags()
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		t, level, msg := parseReplayLine(tags, line)
+		log.LogAt(t, level, msg)
+	}
+	return scanner.Err()
+}
+
+// replayTags 返回 "[tag]" 到等级的映射, 来自各等级的格式模板和等级名称
+func (log *Logger) replayTags() map[string]LogLevel {
+	tags := make(map[string]LogLevel)
+	for _, templates := range []map[LogLevel]string{DefaultFormatterMap, log.formatter} {
+		for level, f := range templates {
+			if strings.HasPrefix(f, "[") {
+				if i := strings.IndexByte(f, ']'); i > 0 && !strings.Contains(f[:i], "%") {
+					tags[f[:i+1]] = level
+				}
+			}
+		}
+	}
+	for level := range log.levels {
+		tags["["+log.levelName(level)+"]"] = level
+	}
+	return tags
+}
+
+// parseReplayLine 解析一行文本日志, 未识别的部分保留在消息中. 未解析到时间时返回零值, 由 LogAt 使用当前时间
+func parseReplayLine(tags map[string]LogLevel, line string) (time.Time, LogLevel, string) {
+	i := strings.IndexByte(line, ']')
+	if !strings.HasPrefix(line, "[") || i < 0 {
+		return time.Time{}, replayDefaultLevel, line
+	}
+	level, ok := tags[line[:i+1]]
+	if !ok {
+		return time.Time{}, replayDefaultLevel, line
+	}
+	msg := strings.TrimSpace(line[i+1:])
+
+	var t time.Time
+	if j := strings.LastIndex(msg, ", "); j >= 0 {
+		if parsed, err := time.ParseInLocation("2006-01-02 15:04.05", msg[j+2:], time.Local); err == nil {
+			t = parsed
+			msg = strings.TrimSpace(msg[:j])
+		}
+	}
+	return t, level, msg
+}
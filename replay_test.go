@@ -0,0 +1,40 @@
+package gologs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogger_Replay(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetJSON(true)
+	log.SetJSONSortKeys(true)
+	log.SetClock(func() time.Time { return time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC) })
+
+	input := strings.Join([]string{
+		"[Warn] disk almost full ",
+		"[-] user logged in , 2023-03-04 05:06.07",
+		"",
+		"[Error] request failed: timeout ",
+		"panic: something odd",
+	}, "\n")
+	if err := log.Replay(strings.NewReader(input)); err != nil {
+		t.Fatal(err)
+	}
+
+	at := time.Date(2023, 3, 4, 5, 6, 7, 0, time.Local).Format(time.RFC3339)
+	want := strings.Join([]string{
+		`{"level":"Warn","message":"disk almost full","time":"2024-06-01T00:00:00Z"}`,
+		`{"level":"Info","message":"user logged in","time":"` + at + `"}`,
+		`{"level":"Error","message":"request failed: timeout","time":"2024-06-01T00:00:00Z"}`,
+		`{"level":"Info","message":"panic: something odd","time":"2024-06-01T00:00:00Z"}`,
+		"",
+	}, "\n")
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected replayed output:\n%s\nwant:\n%s", got, want)
+	}
+}
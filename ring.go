@@ -0,0 +1,102 @@
+package gologs
+
+import "sync"
+
+// BufferPolicy 决定内存缓冲写满后的行为, 见 SetMemoryBufferPolicy
+type BufferPolicy int
+
+const (
+	Overwrite    BufferPolicy = iota // 覆盖最旧的条目, 保留最近的日志 (默认)
+	StopWhenFull                     // 不再接收新条目, 保留最早的日志
+)
+
+// entryRing 是固定容量的日志条目环形缓冲, 写满后按 policy 覆盖最旧的条目或丢弃新条目
+type entryRing struct {
+	mu      sync.Mutex
+	entries []LogEntry
+	next    int
+	full    bool
+	policy  BufferPolicy
+}
+
+func newEntryRing(size int, policy BufferPolicy) *entryRing {
+	return &entryRing{entries: make([]LogEntry, size), policy: policy}
+}
+
+func (r *entryRing) add(e LogEntry) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.full && r.policy == StopWhenFull {
+		return
+	}
+	r.entries[r.next] = e
+	r.next++
+	if r.next == len(r.entries) {
+		r.next = 0
+		r.full = true
+	}
+}
+
+// snapshot 按从旧到新的顺序返回缓冲中的条目副本
+func (r *entryRing) snapshot() []LogEntry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.full {
+		return append([]LogEntry(nil), r.entries[:r.next]...)
+	}
+	out := make([]LogEntry, 0, len(r.entries))
+	out = append(out, r.entries[r.next:]...)
+	return append(out, r.entries[:r.next]...)
+}
+
+// SetErrorBufferSize 设置保留最近 Error 及以上等级日志的条数, n <= 0 时关闭 (默认关闭).
+// 调整大小时保留已有的最新条目 (StopWhenFull 策略下保留最早的条目)
+func (log *Logger) SetErrorBufferSize(n int) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+
+	var old []LogEntry
+	if log.errRing != nil {
+		old = log.errRing.snapshot()
+	}
+	if n <= 0 {
+		log.errRing = nil
+		return
+	}
+	log.errRing = newEntryRing(n, log.bufferPolicy)
+	if len(old) > n {
+		if log.bufferPolicy == StopWhenFull {
+			old = old[:n]
+		} else {
+			old = old[len(old)-n:]
+		}
+	}
+	for _, e := range old {
+		log.errRing.add(e)
+	}
+}
+
+// SetMemoryBufferPolicy 设置 SetErrorBufferSize 开启的内存缓冲写满后的行为: Overwrite 覆盖最旧的条目 (默认),
+// StopWhenFull 停止接收, 保留一次运行中最早的错误. 对已开启的缓冲立即生效
+func (log *Logger) SetMemoryBufferPolicy(policy BufferPolicy) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.bufferPolicy = policy
+	if log.errRing != nil {
+		log.errRing.mu.Lock()
+		log.errRing.policy = policy
+		log.errRing.mu.Unlock()
+	}
+}
+
+// RecentErrors 按时间顺序返回最近的 Error 及以上等级日志, 需先通过 SetErrorBufferSize 开启
+func (log *Logger) RecentErrors() []LogEntry {
+	root := log.root()
+	root.mu.RLock()
+	ring := root.errRing
+	root.mu.RUnlock()
+	if ring == nil {
+		return nil
+	}
+	return ring.snapshot()
+}
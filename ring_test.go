@@ -0,0 +1,78 @@
+package gologs
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestLogger_RecentErrors(t *testing.T) {
+	log := NewLogger(Debug)
+	log.SetOutput(&bytes.Buffer{})
+
+	log.Error("ignored before enabled")
+	if got := log.RecentErrors(); got != nil {
+		t.Fatalf("expected no buffer by default, got %v", got)
+	}
+
+	log.SetErrorBufferSize(2)
+	log.Info("i1")
+	log.Error("e1")
+	log.Warn("w1")
+	log.Error("e2")
+	log.Info("i2")
+	log.Errorf("e%d", 3)
+
+	got := log.RecentErrors()
+	if len(got) != 2 || got[0].Message != "e2" || got[1].Message != "e3" {
+		t.Fatalf("expected [e2 e3], got %v", got)
+	}
+	for _, e := range got {
+		if e.Level != Error {
+			t.Fatalf("unexpected level in error buffer: %v", e.Level)
+		}
+	}
+
+	log.SetErrorBufferSize(1)
+	if got := log.RecentErrors(); len(got) != 1 || got[0].Message != "e3" {
+		t.Fatalf("expected resize to keep newest entry, got %v", got)
+	}
+}
+
+func TestLogger_SetMemoryBufferPolicy(t *testing.T) {
+	messages := func(entries []LogEntry) string {
+		var s string
+		for _, e := range entries {
+			s += e.Message + " "
+		}
+		return s
+	}
+	fill := func(policy BufferPolicy) *Logger {
+		log := NewLogger(Debug)
+		log.SetOutput(&bytes.Buffer{})
+		log.SetMemoryBufferPolicy(policy)
+		log.SetErrorBufferSize(3)
+		for i := 1; i <= 5; i++ {
+			log.Errorf("e%d", i)
+		}
+		return log
+	}
+
+	if got := messages(fill(Overwrite).RecentErrors()); got != "e3 e4 e5 " {
+		t.Fatalf("expected the latest entries with Overwrite, got %q", got)
+	}
+
+	log := fill(StopWhenFull)
+	if got := messages(log.RecentErrors()); got != "e1 e2 e3 " {
+		t.Fatalf("expected the earliest entries with StopWhenFull, got %q", got)
+	}
+	log.SetErrorBufferSize(2)
+	if got := messages(log.RecentErrors()); got != "e1 e2 " {
+		t.Fatalf("expected shrinking to keep the earliest entries, got %q", got)
+	}
+
+	log.SetMemoryBufferPolicy(Overwrite)
+	log.Error("e6")
+	if got := messages(log.RecentErrors()); got != "e2 e6 " {
+		t.Fatalf("expected switching policy to resume overwriting, got %q", got)
+	}
+}
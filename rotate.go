@@ -0,0 +1,96 @@
+package gologs
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+const (
+	// rotateTimeLayout 是按大小轮转时归档文件名中的时间格式
+	rotateTimeLayout = "20060102-150405"
+	// rotateDayLayout 是按天轮转时归档文件名中的日期格式
+	rotateDayLayout = "2006-01-02"
+)
+
+// SetRotateSize 设置日志文件按大小轮转: 写入前若文件将超过 maxBytes, 先将当前文件重命名为
+// LogFileName.时间戳 再打开新的 LogFileName. 文件大小在写入时累加, 不会每次写入都 Stat.
+// maxBytes <= 0 表示不轮转 (默认)
+func (log *Logger) SetRotateSize(maxBytes int64) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.rotateSize = maxBytes
+}
+
+// SetRotateDaily 开启按天轮转: 写入时发现日期与上一次写入不同, 先将当前文件重命名为 LogFileName.2006-01-02
+// (上一次写入的日期) 再打开新的 LogFileName. 日期取自与时间戳相同的时钟 (见 SetClock).
+// 启动时已存在的文件会继续追加, 直到日期变化后才轮转
+func (log *Logger) SetRotateDaily(daily bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.rotateDaily = daily
+}
+
+// rotateDailyIfNeeded 在日期变化时轮转日志文件, 调用方需持有 muf
+func (log *Logger) rotateDailyIfNeeded() error {
+	if !log.rotateDaily {
+		return nil
+	}
+	day := log.now().Format(rotateDayLayout)
+	last := log.fileDay
+	log.fileDay = day
+	if last == "" || last == day {
+		return nil
+	}
+	return log.rotateTo(log.LogFileName + "." + last)
+}
+
+// rotate 将当前日志文件归档并打开新文件, 调用方需持有 muf
+func (log *Logger) rotate() error {
+	return log.rotateTo(log.LogFileName + "." + log.now().Format(rotateTimeLayout))
+}
+
+// rotateTo 关闭当前日志文件, 重命名为 archive (已存在时追加 .1、.2 等序号避免覆盖), 再打开新的日志文件.
+// 开启 SetCompressRotated 时在后台压缩归档. 调用方需持有 muf
+func (log *Logger) rotateTo(archive string) error {
+	log.logFile.Close()
+	log.logFile = nil
+
+	name := archive
+	for i := 1; log.archiveExists(name); i++ {
+		name = archive + "." + strconv.Itoa(i)
+	}
+	renameErr := os.Rename(log.LogFileName, name)
+	if renameErr == nil && log.compressRotated {
+		log.compressWG.Add(1)
+		go log.compressArchive(name)
+	}
+
+	// 重命名失败时继续追加到原文件, 避免丢失日志
+	file, err := os.OpenFile(log.LogFileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		return fmt.Errorf("rotating logfile: %w", err)
+	}
+	log.logFile = file
+	log.fileSize = 0
+	if info, err := file.Stat(); err == nil {
+		log.fileSize = info.Size()
+	}
+	if renameErr != nil {
+		return fmt.Errorf("rotating logfile: %w", renameErr)
+	}
+	return nil
+}
+
+// archiveExists 判断归档名是否已被占用, 开启压缩时同名的 .gz 也视为占用
+func (log *Logger) archiveExists(name string) bool {
+	if _, err := os.Stat(name); !os.IsNotExist(err) {
+		return true
+	}
+	if log.compressRotated {
+		if _, err := os.Stat(name + ".gz"); !os.IsNotExist(err) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,107 @@
+package gologs
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogger_SetRotateSize(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+	log := NewLogger(Debug)
+	log.SetOutput(io.Discard)
+	log.SetClock(func() time.Time { return time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC) })
+	log.SetFile(filename)
+	log.SetIsLogToFile(true)
+	log.SetRotateSize(64)
+
+	// 每行 "[Warn] line N \n" 为 15 字节, 每个文件最多写入 4 行
+	for i := 0; i < 10; i++ {
+		log.Warnf("line %d", i)
+	}
+	log.Close()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+	want := []string{"app.log", "app.log.20240506-070809", "app.log.20240506-070809.1"}
+	if fmt.Sprint(names) != fmt.Sprint(want) {
+		t.Fatalf("expected rotated files %v, got %v", want, names)
+	}
+
+	var all string
+	for _, name := range []string{want[1], want[2], want[0]} {
+		data, _ := os.ReadFile(filepath.Join(dir, name))
+		if len(data) > 64 {
+			t.Fatalf("expected %s to stay below the limit, got %d bytes", name, len(data))
+		}
+		all += string(data)
+	}
+	for i := 0; i < 10; i++ {
+		if !strings.Contains(all, fmt.Sprintf("[Warn] line %d \n", i)) {
+			t.Fatalf("expected line %d to be kept across rotations, got %q", i, all)
+		}
+	}
+}
+
+func TestLogger_SetRotateSizeDisabled(t *testing.T) {
+	dir := t.TempDir()
+	log := NewLogger(Debug)
+	log.SetOutput(io.Discard)
+	log.SetFile(filepath.Join(dir, "app.log"))
+	log.SetIsLogToFile(true)
+
+	for i := 0; i < 10; i++ {
+		log.Warnf("line %d", i)
+	}
+	log.Close()
+
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Fatalf("expected no rotation by default, got %v", entries)
+	}
+}
+
+func TestLogger_SetRotateDaily(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "scraper.log")
+	if err := os.WriteFile(filename, []byte("[Warn] from last run \n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2024, 3, 9, 13, 0, 0, 0, time.Local)
+	log := NewLogger(Debug)
+	log.SetOutput(io.Discard)
+	log.SetClock(func() time.Time { return now })
+	log.SetFile(filename)
+	log.SetIsLogToFile(true)
+	log.SetRotateDaily(true)
+
+	log.Warn("midday")
+	now = now.Add(12 * time.Hour)
+	log.Warn("next day")
+	now = now.Add(time.Hour)
+	log.Warn("still next day")
+	log.Close()
+
+	if got, _ := os.ReadFile(filename + ".2024-03-09"); string(got) != "[Warn] from last run \n[Warn] midday \n" {
+		t.Fatalf("expected previous day archived with existing content, got %q", got)
+	}
+	if got, _ := os.ReadFile(filename); string(got) != "[Warn] next day \n[Warn] still next day \n" {
+		t.Fatalf("expected new day in fresh file, got %q", got)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 2 {
+		t.Fatalf("expected a single rotation, got %v", entries)
+	}
+}
@@ -0,0 +1,29 @@
+package gologs
+
+import "hash/fnv"
+
+// SampleByKey 按 key 的哈希判断是否采样, 约 rate 比例的 key 返回 true. 同一个 key 在任何进程中的结果都相同,
+// 分布式系统各服务对同一请求 ID 调用时会一致地全部输出或全部跳过, 便于追踪关联事件.
+// rate <= 0 时总是返回 false, rate >= 1 时总是返回 true
+//
+//	if gologs.SampleByKey(requestID, 0.1) {
+//		log.Info("request detail")
+//	}
+func SampleByKey(key string, rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	if rate >= 1 {
+		return true
+	}
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	// FNV 对只在末尾几个字节不同的 key 高位分布不够均匀, 再做一次 splitmix64 的混合
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+	return float64(x>>11)/(1<<53) < rate
+}
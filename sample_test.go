@@ -0,0 +1,34 @@
+package gologs
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestSampleByKey(t *testing.T) {
+	for _, key := range []string{"req-1", "req-2", "trace-abc"} {
+		first := SampleByKey(key, 0.5)
+		for i := 0; i < 10; i++ {
+			if SampleByKey(key, 0.5) != first {
+				t.Fatalf("expected a consistent decision for %q", key)
+			}
+		}
+	}
+
+	const n = 20000
+	for _, rate := range []float64{0.1, 0.25, 0.5} {
+		passed := 0
+		for i := 0; i < n; i++ {
+			if SampleByKey("req-"+strconv.Itoa(i), rate) {
+				passed++
+			}
+		}
+		if got := float64(passed) / n; got < rate-0.02 || got > rate+0.02 {
+			t.Fatalf("rate %.2f: expected about %.2f of keys to pass, got %.3f", rate, rate, got)
+		}
+	}
+
+	if SampleByKey("any", 0) || !SampleByKey("any", 1) {
+		t.Fatal("expected rate 0 to reject and rate 1 to accept")
+	}
+}
@@ -0,0 +1,108 @@
+package gologs
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// SetSanitizeControlChars 开启后将消息中的控制字符 (\r、\b、ESC 等) 转义为可见文本, 例如 \r 与 \x1b,
+// 防止子进程输出中的回车覆盖终端行或注入 ANSI 序列. 换行与制表符保持不变, 换行的处理见 SetEscapeNewlines.
+// 开启后消息中的 Hyperlink 也会被转义
+func (log *Logger) SetSanitizeControlChars(b bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.sanitizeControl = b
+}
+
+// SetEscapeNewlines 开启后将消息中的换行替换为 \n 文本, 保证每次日志调用只输出一行,
+// 防止用户可控的数据伪造日志行 (日志注入). 结尾的换行由格式模板产生, 不受影响
+func (log *Logger) SetEscapeNewlines(b bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.escapeNewlines = b
+}
+
+// InvalidUTF8Policy 决定消息中无效 UTF-8 字节的处理方式, 见 SetInvalidUTF8Policy
+type InvalidUTF8Policy int
+
+const (
+	KeepInvalidUTF8 InvalidUTF8Policy = iota // 原样输出 (默认), JSON 编码时会被静默替换为 U+FFFD
+	Replace                                  // 将连续的无效字节替换为一个 U+FFFD
+	HexEscape                                // 将每个无效字节转义为 \xNN 文本, 保留原始字节值便于排查
+)
+
+// SetInvalidUTF8Policy 设置消息中无效 UTF-8 字节 (例如二进制协议的原始数据) 的处理方式,
+// 保证文本与 JSON 日志都是合法的 UTF-8
+func (log *Logger) SetInvalidUTF8Policy(policy InvalidUTF8Policy) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.utf8Policy = policy
+}
+
+// sanitizeMessage 按配置处理消息中的无效 UTF-8、控制字符与换行
+func (log *Logger) sanitizeMessage(msg string) string {
+	if log.utf8Policy != KeepInvalidUTF8 && !utf8.ValidString(msg) {
+		if log.utf8Policy == HexEscape {
+			msg = hexEscapeInvalidUTF8(msg)
+		} else {
+			msg = strings.ToValidUTF8(msg, string(utf8.RuneError))
+		}
+	}
+	if log.sanitizeControl {
+		msg = escapeControlChars(msg)
+	}
+	if log.escapeNewlines && strings.Contains(msg, "\n") {
+		msg = strings.Replace(msg, "\n", `\n`, -1)
+	}
+	return msg
+}
+
+func escapeControlChars(s string) string {
+	if strings.IndexFunc(s, isEscapedControl) < 0 {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if !isEscapedControl(r) {
+			b.WriteRune(r)
+			continue
+		}
+		switch r {
+		case '\r':
+			b.WriteString(`\r`)
+		case '\b':
+			b.WriteString(`\b`)
+		case '\f':
+			b.WriteString(`\f`)
+		case '\v':
+			b.WriteString(`\v`)
+		case '\a':
+			b.WriteString(`\a`)
+		default:
+			fmt.Fprintf(&b, `\x%02x`, r)
+		}
+	}
+	return b.String()
+}
+
+func isEscapedControl(r rune) bool {
+	if r == '\n' || r == '\t' {
+		return false
+	}
+	return r < 0x20 || r == 0x7f || (r >= 0x80 && r < 0xa0)
+}
+
+func hexEscapeInvalidUTF8(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if r == utf8.RuneError && size == 1 {
+			fmt.Fprintf(&b, `\x%02x`, s[i])
+		} else {
+			b.WriteString(s[i : i+size])
+		}
+		i += size
+	}
+	return b.String()
+}
@@ -0,0 +1,73 @@
+package gologs
+
+import (
+	"bytes"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestLogger_SanitizeControlChars(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+
+	msg := "progress 10%\rprogress 100%\b!\x1b[31mred\x1b[0m\tok"
+	log.SetSanitizeControlChars(true)
+	log.Warn(msg)
+	want := "[Warn] progress 10%\\rprogress 100%\\b!\\x1b[31mred\\x1b[0m\tok \n"
+	if got := buf.String(); got != want {
+		t.Fatalf("unexpected sanitized output:\n%q\nwant:\n%q", got, want)
+	}
+
+	buf.Reset()
+	log.SetSanitizeControlChars(false)
+	log.Warn(msg)
+	if got := buf.String(); got != "[Warn] "+msg+" \n" {
+		t.Fatalf("expected raw message when disabled, got %q", got)
+	}
+}
+
+func TestLogger_EscapeNewlines(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetEscapeNewlines(true)
+
+	log.Warnf("user=%s", "bob\n[Error] fake")
+	if got := buf.String(); got != "[Warn] user=bob\\n[Error] fake \n" {
+		t.Fatalf("expected escaped single line, got %q", got)
+	}
+}
+
+func TestLogger_SetInvalidUTF8Policy(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	msg := "frame \xff\xfe ok \xc3"
+
+	log.Warn(msg)
+	if got := buf.String(); got != "[Warn] "+msg+" \n" {
+		t.Fatalf("expected bytes unchanged by default, got %q", got)
+	}
+
+	buf.Reset()
+	log.SetInvalidUTF8Policy(Replace)
+	log.Warn(msg)
+	if got := buf.String(); got != "[Warn] frame � ok � \n" {
+		t.Fatalf("expected replacement characters, got %q", got)
+	}
+
+	buf.Reset()
+	log.SetInvalidUTF8Policy(HexEscape)
+	log.Warn(msg + " 日志")
+	if got := buf.String(); got != `[Warn] frame \xff\xfe ok \xc3 日志 `+"\n" {
+		t.Fatalf("expected hex escapes, got %q", got)
+	}
+
+	buf.Reset()
+	log.SetJSON(true)
+	log.Warn(msg)
+	if !utf8.Valid(buf.Bytes()) || !bytes.Contains(buf.Bytes(), []byte(`"message":"frame \\xff\\xfe ok \\xc3"`)) {
+		t.Fatalf("expected valid json with hex escapes, got %q", buf.String())
+	}
+}
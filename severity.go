@@ -0,0 +1,63 @@
+package gologs
+
+// SeverityMapper 将 LogLevel 映射为外部系统的严重程度名称, 用于 JSON 输出的 severity 字段
+type SeverityMapper func(LogLevel) string
+
+// SyslogSeverity 按 RFC 5424 的关键字映射 (debug、info、notice、warning、err、crit)
+func SyslogSeverity(level LogLevel) string {
+	switch {
+	case level < Info:
+		return "debug"
+	case level < Important:
+		return "info"
+	case level < Warn:
+		return "notice"
+	case level < Error:
+		return "warning"
+	case level < Fatal:
+		return "err"
+	default:
+		return "crit"
+	}
+}
+
+// GCPSeverity 按 Google Cloud Logging 的 LogSeverity 映射
+func GCPSeverity(level LogLevel) string {
+	switch {
+	case level < Info:
+		return "DEBUG"
+	case level < Important:
+		return "INFO"
+	case level < Warn:
+		return "NOTICE"
+	case level < Error:
+		return "WARNING"
+	case level < Fatal:
+		return "ERROR"
+	default:
+		return "CRITICAL"
+	}
+}
+
+// ECSSeverity 按 Elastic Common Schema 常用的 log.level 取值映射
+func ECSSeverity(level LogLevel) string {
+	switch {
+	case level < Info:
+		return "debug"
+	case level < Warn:
+		return "info"
+	case level < Error:
+		return "warn"
+	case level < Fatal:
+		return "error"
+	default:
+		return "fatal"
+	}
+}
+
+// SetSeverityMapper 设置 JSON 输出中 severity 字段的映射函数, 为 nil 时不输出 severity (默认)
+func (log *Logger) SetSeverityMapper(mapper SeverityMapper) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.severityMapper = mapper
+}
@@ -0,0 +1,62 @@
+package gologs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLogger_SeverityMapper(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetJSON(true)
+
+	log.Warn("no mapper")
+	if strings.Contains(buf.String(), `"severity"`) {
+		t.Fatalf("expected no severity without a mapper, got %q", buf.String())
+	}
+
+	buf.Reset()
+	log.SetSeverityMapper(func(level LogLevel) string {
+		if level >= Error {
+			return "PAGE"
+		}
+		return "TICKET"
+	})
+	log.Error("custom")
+	if !strings.Contains(buf.String(), `"severity":"PAGE"`) {
+		t.Fatalf("expected custom severity, got %q", buf.String())
+	}
+
+	buf.Reset()
+	log.SetSeverityMapper(GCPSeverity)
+	log.Warn("gcp")
+	if !strings.Contains(buf.String(), `"severity":"WARNING"`) {
+		t.Fatalf("expected gcp severity, got %q", buf.String())
+	}
+}
+
+func TestBuiltinSeverityMappers(t *testing.T) {
+	cases := []struct {
+		level            LogLevel
+		syslog, gcp, ecs string
+	}{
+		{Debug, "debug", "DEBUG", "debug"},
+		{Info, "info", "INFO", "info"},
+		{Important, "notice", "NOTICE", "info"},
+		{Warn, "warning", "WARNING", "warn"},
+		{Error, "err", "ERROR", "error"},
+	}
+	for _, c := range cases {
+		if got := SyslogSeverity(c.level); got != c.syslog {
+			t.Errorf("SyslogSeverity(%s) = %s, want %s", c.level.Name(), got, c.syslog)
+		}
+		if got := GCPSeverity(c.level); got != c.gcp {
+			t.Errorf("GCPSeverity(%s) = %s, want %s", c.level.Name(), got, c.gcp)
+		}
+		if got := ECSSeverity(c.level); got != c.ecs {
+			t.Errorf("ECSSeverity(%s) = %s, want %s", c.level.Name(), got, c.ecs)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package gologs
+
+import (
+	"io"
+	"time"
+)
+
+// LogEntry 是一条日志在渲染为文本或 JSON 之前的结构化表示
+type LogEntry struct {
+	Time    time.Time
+	Level   LogLevel
+	Message string
+	Fields  []Field
+	Caller  *Caller // 未开启 SetReportCaller 时为 nil
+
+	color func(string) string // 单次调用指定的颜色, 见 LogColored
+}
+
+// Formatter 将日志条目渲染为一行输出 (包含结尾换行)
+type Formatter interface {
+	Format(log *Logger, e *LogEntry) string
+}
+
+// TextFormatter 使用按等级配置的文本模板渲染日志, 模板语法与 SetFormatter 相同,
+// 未配置的等级回退到 DefaultFormatterMap
+type TextFormatter map[LogLevel]string
+
+func (f TextFormatter) Format(log *Logger, e *LogEntry) string {
+	return log.formatText(f, e)
+}
+
+// JSONFormatter 将日志渲染为一行 JSON, 与 SetJSON(true) 的输出相同
+type JSONFormatter struct{}
+
+func (JSONFormatter) Format(log *Logger, e *LogEntry) string {
+	return log.formatJSON(e)
+}
+
+// Sink 是一个独立的日志输出目的地, 拥有自己的最低等级、格式与颜色设置.
+// Formatter 为 nil 时使用 Logger 当前的输出格式
+type Sink struct {
+	Writer    io.Writer
+	MinLevel  LogLevel
+	Formatter Formatter
+	Color     bool
+}
+
+// AddSink 添加一个输出目的地. 日志需先通过 Logger 自身的 Level 过滤, 再按 sink 的 MinLevel 过滤
+func (log *Logger) AddSink(sink Sink) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.extSinks = append(log.extSinks, sink)
+}
+
+// sinks 返回本次输出的全部 sink: writer 与日志文件作为内置 sink 排在最前, 之后是 AddSink 添加的 sink.
+// color 表示 writer 是否输出颜色, fanout 为 true (使用默认输出) 时 AddOutput 添加的 writer 紧随 writer 之后
+func (log *Logger) sinks(writer io.Writer, color, fanout bool) []Sink {
+	sinks := make([]Sink, 0, len(log.extSinks)+len(log.outputs)+3)
+	if fanout {
+		sinks = append(sinks, log.outputSinks(writer, color)...)
+	} else {
+		sinks = append(sinks, Sink{Writer: writer, Color: color})
+	}
+	if log.LogToFile {
+		sinks = append(sinks, Sink{Writer: logFileWriter{log}})
+	}
+	if log.mmapFile != nil {
+		sinks = append(sinks, Sink{Writer: mmapWriter{log}})
+	}
+	return append(sinks, log.extSinks...)
+}
+
+// logFileWriter 将日志文件适配为 sink 使用的 io.Writer
+type logFileWriter struct {
+	log *Logger
+}
+
+func (w logFileWriter) Write(p []byte) (int, error) {
+	w.log.writeToFile(string(p))
+	return len(p), nil
+}
+
+// OnEmit 注册一个回调, 每条实际输出的日志都会以完整的 LogEntry 调用它, 可注册多个, 按注册顺序调用.
+// 回调在释放 Logger 的锁之后执行, 主要用于测试断言和统计
+func (log *Logger) OnEmit(fn func(LogEntry)) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	callbacks := make([]func(LogEntry), len(log.onEmit), len(log.onEmit)+1)
+	copy(callbacks, log.onEmit)
+	log.onEmit = append(callbacks, fn)
+}
+
+func (log *Logger) notifyEmit(e *LogEntry) {
+	log.mu.RLock()
+	callbacks, hooks := log.onEmit, log.hooks
+	log.mu.RUnlock()
+	for _, fn := range callbacks {
+		fn(*e)
+	}
+	for _, hook := range hooks {
+		log.runHook(hook, e)
+	}
+}
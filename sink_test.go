@@ -0,0 +1,96 @@
+package gologs
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLogger_AddSink(t *testing.T) {
+	var main, info, errs bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&main)
+	log.SuffixFunc = func() string { return "" }
+
+	log.AddSink(Sink{
+		Writer:    &info,
+		MinLevel:  Info,
+		Formatter: TextFormatter{Info: "I %s\n", Error: "E %s\n"},
+	})
+	log.AddSink(Sink{
+		Writer:    &errs,
+		MinLevel:  Error,
+		Formatter: JSONFormatter{},
+		Color:     true,
+	})
+
+	log.Debug("d")
+	log.Info("i")
+	log.Error("e")
+
+	if got := info.String(); got != "I i\nE e\n" {
+		t.Fatalf("unexpected info sink output: %q", got)
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(errs.Bytes(), &obj); err != nil {
+		t.Fatalf("expected a single uncolored json line, got %q: %v", errs.String(), err)
+	}
+	if obj["level"] != "Error" || obj["message"] != "e" {
+		t.Fatalf("unexpected error sink entry: %v", obj)
+	}
+
+	if got := main.String(); got != "[Debug] d \n[-] i \n[Error] e \n" {
+		t.Fatalf("unexpected main output: %q", got)
+	}
+}
+
+func TestLogger_PreWriteHook(t *testing.T) {
+	var main, extra bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&main)
+	log.AddSink(Sink{Writer: &extra, Formatter: TextFormatter{Warn: "%s\n"}})
+	log.SetPreWriteHook(func(level LogLevel, line string) (string, bool) {
+		if strings.Contains(line, "skip") {
+			return line, false
+		}
+		return strings.ToUpper(line), true
+	})
+
+	log.Warn("keep me")
+	log.Warn("skip me")
+
+	if got := main.String(); got != "[WARN] KEEP ME \n" {
+		t.Fatalf("unexpected main output: %q", got)
+	}
+	if got := extra.String(); got != "KEEP ME\n" {
+		t.Fatalf("unexpected sink output: %q", got)
+	}
+}
+
+func TestLogger_OnEmit(t *testing.T) {
+	log := NewLogger(Info)
+	log.SetOutput(&bytes.Buffer{})
+
+	var first, second []LogEntry
+	log.OnEmit(func(e LogEntry) { first = append(first, e) })
+	log.OnEmit(func(e LogEntry) {
+		second = append(second, e)
+		// 回调在锁外执行, 可以修改 Logger 配置而不会死锁
+		log.SetLevel(Info)
+	})
+
+	log.Debug("filtered")
+	log.Info("one")
+	log.Named("db").Warnf("two %d", 2)
+
+	for _, got := range [][]LogEntry{first, second} {
+		if len(got) != 2 || got[0].Message != "one" || got[1].Message != "two 2" || got[1].Level != Warn {
+			t.Fatalf("unexpected emitted entries: %+v", got)
+		}
+		if len(got[1].Fields) != 1 || got[1].Fields[0].Value != "db" {
+			t.Fatalf("expected structured fields in entry: %+v", got[1].Fields)
+		}
+	}
+}
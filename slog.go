@@ -0,0 +1,109 @@
+package gologs
+
+import (
+	"context"
+	"log/slog"
+)
+
+// NewSlogHandler 返回将 log/slog 记录桥接到 l 的 slog.Handler: slog 等级映射到最接近的 LogLevel,
+// 属性作为结构化字段输出 (分组以 "." 连接为键名), 等级过滤、输出目的地、格式与文件轮转均沿用 l 的设置.
+// 开启 SetReportCaller 时调用位置取自 slog.Record
+//
+//	logger := slog.New(gologs.NewSlogHandler(gologs.Log))
+//	logger.Info("user login", "user", "alice")
+func NewSlogHandler(l *Logger) slog.Handler {
+	return &slogHandler{log: l}
+}
+
+type slogHandler struct {
+	log    *Logger
+	fields []Field
+	prefix string // WithGroup 累积的分组前缀, 形如 "req."
+}
+
+// slogLevel 将 slog 等级映射为最接近的 LogLevel
+func slogLevel(level slog.Level) LogLevel {
+	switch {
+	case level < slog.LevelInfo:
+		return Debug
+	case level < slog.LevelWarn:
+		return Info
+	case level < slog.LevelError:
+		return Warn
+	default:
+		return Error
+	}
+}
+
+func (h *slogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.log.root().levelEnabled(slogLevel(level))
+}
+
+func (h *slogHandler) Handle(_ context.Context, r slog.Record) error {
+	fields := h.fields
+	if r.NumAttrs() > 0 {
+		var extra []Field
+		r.Attrs(func(a slog.Attr) bool {
+			extra = appendSlogAttr(extra, h.prefix, a)
+			return true
+		})
+		fields = mergeFields(fields, extra)
+	}
+
+	level := slogLevel(r.Level)
+	root := h.log.root()
+	root.mu.RLock()
+	if !root.enabled(level) {
+		root.mu.RUnlock()
+		root.reportErrors()
+		return nil
+	}
+	t := r.Time
+	if t.IsZero() {
+		t = root.now()
+	}
+	e := root.newEntry(t, level, h.log.withFields(fields), r.Message)
+	if root.reportCaller && r.PC != 0 {
+		e.Caller = callerFromPC(r.PC)
+	}
+	emitted := root.output(nil, e)
+	root.mu.RUnlock()
+	root.reportErrors()
+	if emitted {
+		root.notifyEmit(e)
+	}
+	return nil
+}
+
+func (h *slogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	var extra []Field
+	for _, a := range attrs {
+		extra = appendSlogAttr(extra, h.prefix, a)
+	}
+	return &slogHandler{log: h.log, fields: mergeFields(h.fields, extra), prefix: h.prefix}
+}
+
+func (h *slogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &slogHandler{log: h.log, fields: h.fields, prefix: h.prefix + name + "."}
+}
+
+// appendSlogAttr 将 a 展开为字段追加到 fields, 分组属性递归展开, 按 slog 的约定忽略空属性
+func appendSlogAttr(fields []Field, prefix string, a slog.Attr) []Field {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return fields
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			fields = appendSlogAttr(fields, prefix, ga)
+		}
+		return fields
+	}
+	return append(fields, Field{Key: prefix + a.Key, Value: a.Value.Any()})
+}
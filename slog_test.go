@@ -0,0 +1,60 @@
+package gologs
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Info)
+	log.SetOutput(&buf)
+	logger := slog.New(NewSlogHandler(log))
+
+	logger.Debug("hidden")
+	logger.Warn("disk full", "pct", 91, slog.Group("disk", "dev", "sda"))
+	if got := buf.String(); got != "[Warn] disk full pct=91 disk.dev=sda \n" {
+		t.Fatalf("unexpected slog text output %q", got)
+	}
+	if logger.Enabled(context.Background(), slog.LevelDebug) {
+		t.Fatal("expected Debug to be disabled by the logger threshold")
+	}
+
+	buf.Reset()
+	log.SetJSON(true)
+	log.SetReportCaller(true)
+	line := currentLine() + 1
+	logger.With("request_id", "r1").WithGroup("req").Error("failed", "method", "GET")
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &obj); err != nil {
+		t.Fatal(err)
+	}
+	if obj["level"] != "Error" || obj["message"] != "failed" || obj["request_id"] != "r1" || obj["req.method"] != "GET" {
+		t.Fatalf("unexpected slog json output %v", obj)
+	}
+	caller, _ := obj["caller"].(map[string]interface{})
+	if !strings.HasSuffix(caller["file"].(string), "slog_test.go") || caller["line"] != float64(line) {
+		t.Fatalf("expected caller slog_test.go:%d, got %v", line, caller)
+	}
+}
+
+func TestSlogLevel(t *testing.T) {
+	cases := map[slog.Level]LogLevel{
+		slog.LevelDebug:     Debug,
+		slog.LevelInfo:      Info,
+		slog.LevelInfo + 2:  Info,
+		slog.LevelWarn:      Warn,
+		slog.LevelError:     Error,
+		slog.LevelError + 4: Error,
+	}
+	for in, want := range cases {
+		if got := slogLevel(in); got != want {
+			t.Fatalf("slogLevel(%v) = %d, want %d", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package gologs
+
+import (
+	"fmt"
+	"hash/fnv"
+	"runtime"
+	"strings"
+)
+
+// maxStackHashes 为堆栈去重时记住的堆栈数量上限
+const maxStackHashes = 256
+
+// 堆栈信息作为结构化字段输出
+const (
+	stackKey   = "stack"
+	stackIDKey = "stack_id"
+)
+
+// SetStackTrace 为 level 及以上等级的日志附加调用堆栈 (stack 字段, 以及标识该堆栈的 stack_id).
+// 相同的堆栈再次出现时不再完整输出, stack 字段只包含 "<stack_id> (see above)" 引用第一次的输出.
+// 最多记住最近 maxStackHashes 个堆栈, level <= 0 关闭 (默认)
+func (log *Logger) SetStackTrace(level LogLevel) {
+	log.mu.Lock()
+	log.stackLevel = level
+	log.mu.Unlock()
+
+	log.stackMu.Lock()
+	defer log.stackMu.Unlock()
+	log.stackSeen = nil
+	log.stackOrder = nil
+}
+
+// stackAt 返回调用 stackAt 的函数往上 skip 层开始的调用堆栈, 只包含函数与位置, 相同调用路径得到相同的文本
+func stackAt(skip int) string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(skip+2, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	var b strings.Builder
+	for {
+		f, more := frames.Next()
+		fmt.Fprintf(&b, "%s\n\t%s:%d", f.Function, f.File, f.Line)
+		if !more {
+			break
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// stackFields 返回堆栈对应的字段, 已输出过的堆栈只返回引用
+func (log *Logger) stackFields(stack string) []Field {
+	h := fnv.New32a()
+	h.Write([]byte(stack))
+	id := fmt.Sprintf("%08x", h.Sum32())
+	if log.seenStack(id) {
+		return []Field{{Key: stackKey, Value: id + " (see above)"}}
+	}
+	return []Field{{Key: stackIDKey, Value: id}, {Key: stackKey, Value: stack}}
+}
+
+// seenStack 判断 id 是否已经输出过, 未输出过时记录下来, 超出上限时淘汰最早的记录
+func (log *Logger) seenStack(id string) bool {
+	log.stackMu.Lock()
+	defer log.stackMu.Unlock()
+	if log.stackSeen[id] {
+		return true
+	}
+	if log.stackSeen == nil {
+		log.stackSeen = make(map[string]bool)
+	}
+	if len(log.stackOrder) >= maxStackHashes {
+		delete(log.stackSeen, log.stackOrder[0])
+		log.stackOrder = log.stackOrder[1:]
+	}
+	log.stackSeen[id] = true
+	log.stackOrder = append(log.stackOrder, id)
+	return false
+}
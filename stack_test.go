@@ -0,0 +1,50 @@
+package gologs
+
+import (
+	"bytes"
+	"errors"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestLogger_StackTraceDedup(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetStackTrace(Error)
+
+	err := errors.New("connection reset")
+	for i := 0; i < 2; i++ {
+		log.Error(err)
+	}
+	log.Warn("no stack below the threshold")
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 lines, got %q", buf.String())
+	}
+	m := regexp.MustCompile(`^\[Error\] connection reset stack_id=([0-9a-f]{8}) stack=".*TestLogger_StackTraceDedup.*stack_test.go`).FindStringSubmatch(lines[0])
+	if m == nil {
+		t.Fatalf("expected full stack on first occurrence, got %q", lines[0])
+	}
+	if want := `[Error] connection reset stack="` + m[1] + ` (see above)" `; lines[1] != want {
+		t.Fatalf("expected hash reference on repeat, got %q want %q", lines[1], want)
+	}
+	if lines[2] != "[Warn] no stack below the threshold " {
+		t.Fatalf("expected no stack for lower levels, got %q", lines[2])
+	}
+}
+
+func TestLogger_StackTraceCacheBounded(t *testing.T) {
+	log := NewLogger(Debug)
+	for i := 0; i < maxStackHashes+10; i++ {
+		log.seenStack(strings.Repeat("x", i))
+	}
+	if len(log.stackSeen) != maxStackHashes || len(log.stackOrder) != maxStackHashes {
+		t.Fatalf("expected cache bounded to %d, got %d", maxStackHashes, len(log.stackSeen))
+	}
+	if log.seenStack("") {
+		t.Fatal("expected oldest stack to be evicted")
+	}
+}
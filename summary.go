@@ -0,0 +1,54 @@
+package gologs
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// SetSummaryOnClose 开启后统计每个等级输出的日志条数, 并在 Close 时向控制台输出一行汇总,
+// 例如 "[Summary] 2 Info, 3 Warn, 1 Error". 只统计开启之后的日志, Quiet 时不输出汇总
+func (log *Logger) SetSummaryOnClose(b bool) {
+	log.countMu.Lock()
+	defer log.countMu.Unlock()
+	log.summaryOnClose = b
+	if b && log.counts == nil {
+		log.counts = make(map[LogLevel]int)
+	}
+}
+
+func (log *Logger) countEntry(level LogLevel) {
+	log.countMu.Lock()
+	defer log.countMu.Unlock()
+	if log.summaryOnClose {
+		log.counts[level]++
+	}
+}
+
+func (log *Logger) writeSummary() {
+	log.countMu.Lock()
+	if !log.summaryOnClose {
+		log.countMu.Unlock()
+		return
+	}
+	levels := make([]LogLevel, 0, len(log.counts))
+	for level := range log.counts {
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+	parts := make([]string, 0, len(levels))
+	for _, level := range levels {
+		parts = append(parts, fmt.Sprintf("%d %s", log.counts[level], log.levelName(level)))
+	}
+	log.countMu.Unlock()
+
+	log.mu.RLock()
+	defer log.mu.RUnlock()
+	if log.Quiet {
+		return
+	}
+	if len(parts) == 0 {
+		parts = append(parts, "no messages")
+	}
+	writeString(log.writer, fmt.Sprintf("[Summary] %s\n", strings.Join(parts, ", ")))
+}
@@ -0,0 +1,41 @@
+package gologs
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestLogger_SummaryOnClose(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Info)
+	log.SetOutput(&buf)
+	log.SetSummaryOnClose(true)
+
+	log.Warn("w1")
+	log.Error("e1")
+	log.Warnf("w%d", 2)
+	log.Info("i1")
+	log.Debug("filtered")
+	log.Close()
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if got := lines[len(lines)-1]; got != "[Summary] 1 Info, 2 Warn, 1 Error" {
+		t.Fatalf("unexpected summary: %q", got)
+	}
+}
+
+func TestLogger_SummaryOnCloseQuiet(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Info)
+	log.SetOutput(&buf)
+	log.SetSummaryOnClose(true)
+	log.Warn("w1")
+
+	buf.Reset()
+	log.SetQuiet(true)
+	log.Close()
+	if buf.Len() != 0 {
+		t.Fatalf("expected no summary when quiet, got %q", buf.String())
+	}
+}
@@ -0,0 +1,27 @@
+package gologs
+
+import "fmt"
+
+// SetSyncOnWrite 开启后每次写日志文件都调用 Sync 将数据落盘, 进程崩溃时不会丢失最后几行, 适用于审计日志.
+// 每次写入都 Sync 较慢, 默认关闭, 也可以只在需要时调用 Sync
+func (log *Logger) SetSyncOnWrite(b bool) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.syncOnWrite = b
+}
+
+// Sync 将日志文件已写入的数据落盘, 未打开日志文件时直接返回 nil. 可以定期调用, 在性能与持久性之间折中
+func (log *Logger) Sync() error {
+	root := log.root()
+	root.mu.RLock()
+	defer root.mu.RUnlock()
+	root.muf.Lock()
+	defer root.muf.Unlock()
+	if root.logFile == nil {
+		return nil
+	}
+	if err := root.logFile.Sync(); err != nil {
+		return fmt.Errorf("syncing logfile: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package gologs
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLogger_SetSyncOnWrite(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "audit.log")
+	log := NewLogger(Debug)
+	log.SetOutput(io.Discard)
+	log.SetFile(filename)
+	log.SetIsLogToFile(true)
+	log.SetSyncOnWrite(true)
+	defer log.Close()
+
+	log.Warn("durable")
+	if got, _ := os.ReadFile(filename); string(got) != "[Warn] durable \n" {
+		t.Fatalf("expected the line on disk, got %q", got)
+	}
+	if err := log.LastWriteError(); err != nil {
+		t.Fatalf("unexpected write error %v", err)
+	}
+	if err := log.Sync(); err != nil {
+		t.Fatalf("unexpected Sync error %v", err)
+	}
+}
+
+func TestLogger_SyncErrors(t *testing.T) {
+	log := NewLogger(Debug)
+	if err := log.Sync(); err != nil {
+		t.Fatalf("expected nil without a log file, got %v", err)
+	}
+
+	log = newBrokenFileLogger(t)
+	if err := log.Sync(); !errors.Is(err, os.ErrClosed) {
+		t.Fatalf("expected Sync to report the closed file, got %v", err)
+	}
+}
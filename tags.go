@@ -0,0 +1,46 @@
+package gologs
+
+import "strings"
+
+// tagsKey 为 Tagged 附加的标签字段, JSON 中输出为字符串数组
+const tagsKey = "tags"
+
+// tagList 是 Tagged 附加的标签, 以独立类型区分用户自己设置的同名字段
+type tagList []string
+
+// Tagged 以 level 输出格式化消息并附加轻量标签, 便于 grep: 文本中以 "#db #slow" 的形式紧跟在消息之后,
+// JSON 中为 tags 数组. 标签开头的 # 可省略, 空标签被忽略
+//
+//	log.Tagged(gologs.Warn, []string{"db", "slow"}, "query took %s", elapsed)
+func (log *Logger) Tagged(level LogLevel, tags []string, format string, s ...interface{}) {
+	list := make(tagList, 0, len(tags))
+	for _, tag := range tags {
+		if tag = strings.TrimPrefix(tag, "#"); tag != "" {
+			list = append(list, tag)
+		}
+	}
+	var fields []Field
+	if len(list) > 0 {
+		fields = []Field{{Key: tagsKey, Value: list}}
+	}
+	log.logInterfacef(nil, level, fields, format, s...)
+}
+
+// splitTags 从 fields 中取出 Tagged 附加的标签, 返回 " #a #b" 形式的文本与其余字段
+func splitTags(fields []Field) (string, []Field) {
+	for i, f := range fields {
+		tags, ok := f.Value.(tagList)
+		if !ok || f.Key != tagsKey {
+			continue
+		}
+		var b strings.Builder
+		for _, tag := range tags {
+			b.WriteString(" #")
+			b.WriteString(tag)
+		}
+		rest := make([]Field, 0, len(fields)-1)
+		rest = append(rest, fields[:i]...)
+		return b.String(), append(rest, fields[i+1:]...)
+	}
+	return "", fields
+}
@@ -0,0 +1,39 @@
+package gologs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestLogger_Tagged(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+
+	log.With(map[string]interface{}{"db": "orders"}).Tagged(Warn, []string{"db", "#slow", ""}, "query took %dms", 1200)
+	if got := buf.String(); got != "[Warn] query took 1200ms #db #slow db=orders \n" {
+		t.Fatalf("unexpected tagged text output %q", got)
+	}
+
+	buf.Reset()
+	log.Tagged(Warn, nil, "untagged")
+	if got := buf.String(); got != "[Warn] untagged \n" {
+		t.Fatalf("expected no tags, got %q", got)
+	}
+
+	buf.Reset()
+	log.SetJSON(true)
+	log.Tagged(Error, []string{"db", "slow"}, "failed")
+	var obj struct {
+		Message string   `json:"message"`
+		Tags    []string `json:"tags"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &obj); err != nil {
+		t.Fatal(err)
+	}
+	if obj.Message != "failed" || fmt.Sprint(obj.Tags) != "[db slow]" {
+		t.Fatalf("expected a tags array in json, got %q", buf.String())
+	}
+}
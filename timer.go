@@ -0,0 +1,35 @@
+package gologs
+
+import "time"
+
+// slowKey 为 TimerThreshold 计时超过阈值时追加的标记字段
+const slowKey = "slow"
+
+// TimerThreshold 开始为 name 计时, 返回的 stop 函数输出 "name took 耗时": 耗时不超过 warnOver 时以 Debug 输出,
+// 超过时以 Warn 输出并追加 slow=true, 无需手动比较即可发现慢操作. 耗时取自与时间戳相同的时钟 (见 SetClock)
+//
+//	defer log.TimerThreshold("db.query", 200*time.Millisecond)()
+func (log *Logger) TimerThreshold(name string, warnOver time.Duration) (stop func()) {
+	start := log.root().clockNow()
+	return func() {
+		elapsed := log.root().clockNow().Sub(start)
+		msg := name + " took " + compactDuration(elapsed)
+		if elapsed > warnOver {
+			log.logInterface(nil, Warn, []Field{{Key: slowKey, Value: true}}, msg)
+		} else {
+			log.logInterface(nil, Debug, nil, msg)
+		}
+	}
+}
+
+// compactDuration 按量级保留精度: 1 秒以上精确到毫秒, 1 毫秒以上精确到微秒, 例如 1.234s、15.2ms
+func compactDuration(d time.Duration) string {
+	switch {
+	case d >= time.Second:
+		return d.Round(time.Millisecond).String()
+	case d >= time.Millisecond:
+		return d.Round(time.Microsecond).String()
+	default:
+		return d.String()
+	}
+}
@@ -0,0 +1,30 @@
+package gologs
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestLogger_TimerThreshold(t *testing.T) {
+	var buf bytes.Buffer
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	log.SetClock(func() time.Time { return now })
+
+	stop := log.TimerThreshold("cache.get", 100*time.Millisecond)
+	now = now.Add(15200 * time.Microsecond)
+	stop()
+	if got := buf.String(); got != "[Debug] cache.get took 15.2ms \n" {
+		t.Fatalf("expected a debug line for the fast operation, got %q", got)
+	}
+
+	buf.Reset()
+	stop = log.TimerThreshold("db.query", 100*time.Millisecond)
+	now = now.Add(1234567 * time.Microsecond)
+	stop()
+	if got := buf.String(); got != "[Warn] db.query took 1.235s slow=true \n" {
+		t.Fatalf("expected a slow warning, got %q", got)
+	}
+}
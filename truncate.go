@@ -0,0 +1,105 @@
+package gologs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// SetMaxSizeTruncate 设置日志文件的大小上限. 写入后文件超过 maxBytes 时只保留末尾约 keepTailBytes 字节
+// (从完整的行开始), 不产生轮转文件. 新内容先写入同目录的临时文件再重命名覆盖, 崩溃时不会丢失原文件.
+// maxBytes <= 0 关闭该功能
+func (log *Logger) SetMaxSizeTruncate(maxBytes, keepTailBytes int64) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	if keepTailBytes < 0 {
+		keepTailBytes = 0
+	}
+	log.truncateMax = maxBytes
+	log.truncateKeep = keepTailBytes
+}
+
+// truncateIfNeeded 在文件超过上限时截断到末尾, 调用方需持有 muf
+func (log *Logger) truncateIfNeeded() error {
+	if log.truncateMax <= 0 {
+		return nil
+	}
+	if log.fileSize <= log.truncateMax {
+		return nil
+	}
+	if err := log.truncateTail(log.fileSize); err != nil {
+		return fmt.Errorf("truncating logfile: %w", err)
+	}
+	return nil
+}
+
+func (log *Logger) truncateTail(size int64) error {
+	tail, err := readTail(log.LogFileName, size, log.truncateKeep)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(log.LogFileName), filepath.Base(log.LogFileName)+".tmp*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(tail)
+	if err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	// Windows 下不能重命名覆盖已打开的文件, 先关闭原文件; 重命名失败时重新打开原文件继续写
+	log.logFile.Close()
+	renameErr := os.Rename(tmp.Name(), log.LogFileName)
+	if renameErr != nil {
+		os.Remove(tmp.Name())
+	}
+	file, err := os.OpenFile(log.LogFileName, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0666)
+	if err != nil {
+		log.logFile = nil
+		return err
+	}
+	log.logFile = file
+	log.fileSize = int64(len(tail))
+	if renameErr != nil {
+		if info, err := file.Stat(); err == nil {
+			log.fileSize = info.Size()
+		}
+	}
+	return renameErr
+}
+
+// readTail 读取文件最后 keep 字节, 并丢弃开头不完整的一行
+func readTail(name string, size, keep int64) ([]byte, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	start := size - keep
+	if start < 0 {
+		start = 0
+	}
+	tail := make([]byte, size-start)
+	if _, err := f.ReadAt(tail, start); err != nil && err != io.EOF {
+		return nil, err
+	}
+	if start > 0 {
+		if i := bytes.IndexByte(tail, '\n'); i >= 0 {
+			tail = tail[i+1:]
+		} else {
+			tail = nil
+		}
+	}
+	return tail, nil
+}
@@ -0,0 +1,51 @@
+package gologs
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogger_SetMaxSizeTruncate(t *testing.T) {
+	dir := t.TempDir()
+	filename := filepath.Join(dir, "app.log")
+	log := NewLogger(Debug)
+	log.SetOutput(io.Discard)
+	log.SetFile(filename)
+	log.SetIsLogToFile(true)
+	log.SetMaxSizeTruncate(1024, 256)
+
+	for i := 0; i < 100; i++ {
+		log.Warnf("line %03d", i)
+	}
+	log.Close()
+
+	got, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) > 1024 || len(got) == 0 {
+		t.Fatalf("expected file to stay below the limit, got %d bytes", len(got))
+	}
+	if !strings.HasSuffix(string(got), "[Warn] line 099 \n") || strings.Contains(string(got), "line 000") {
+		t.Fatalf("expected most recent content to be preserved, got %q", got)
+	}
+	if !strings.HasPrefix(string(got), "[Warn] line ") {
+		t.Fatalf("expected tail to start at a line boundary, got %q", got)
+	}
+
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Fatalf("expected no temp files left behind, got %v", entries)
+	}
+
+	// 截断后文件恢复为追加写入
+	log.SetIsLogToFile(true)
+	log.Warn("after")
+	log.Close()
+	if got, _ := os.ReadFile(filename); !strings.HasSuffix(string(got), "line 099 \n[Warn] after \n") {
+		t.Fatalf("expected appends after truncation, got %q", got)
+	}
+}
@@ -0,0 +1,50 @@
+package gologs
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ValidateJSONOutput 检查一行 JSON 日志是否包含 required 中的全部字段, 主要用于测试中发现字段被重命名或丢失.
+// required 的每一项为 "key" 或 "key:type", type 可选 string、number、bool、object、array、null,
+// 指定 type 时同时校验字段类型
+func ValidateJSONOutput(line string, required ...string) error {
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(line)), &obj); err != nil {
+		return fmt.Errorf("gologs: invalid json line: %w", err)
+	}
+
+	for _, spec := range required {
+		key, typ, hasType := strings.Cut(spec, ":")
+		v, ok := obj[key]
+		if !ok {
+			return fmt.Errorf("gologs: missing required field %q", key)
+		}
+		if hasType {
+			if got := jsonTypeName(v); got != typ {
+				return fmt.Errorf("gologs: field %q has type %s, want %s", key, got, typ)
+			}
+		}
+	}
+	return nil
+}
+
+func jsonTypeName(v interface{}) string {
+	switch v.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "bool"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", v)
+	}
+}
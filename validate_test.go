@@ -0,0 +1,23 @@
+package gologs
+
+import "testing"
+
+func TestValidateJSONOutput(t *testing.T) {
+	line := `{"time":"2024-01-02T03:04:05Z","level":"Warn","message":"disk full","attempt":3,"ok":false}` + "\n"
+
+	if err := ValidateJSONOutput(line, "time:string", "level", "message:string", "attempt:number", "ok:bool"); err != nil {
+		t.Fatalf("expected conforming line to pass, got %v", err)
+	}
+
+	if err := ValidateJSONOutput(line, "message", "request_id"); err == nil {
+		t.Fatal("expected missing field to fail")
+	}
+
+	if err := ValidateJSONOutput(line, "attempt:string"); err == nil {
+		t.Fatal("expected wrong field type to fail")
+	}
+
+	if err := ValidateJSONOutput("[Warn] not json", "message"); err == nil {
+		t.Fatal("expected non-json line to fail")
+	}
+}
@@ -0,0 +1,30 @@
+package gologs
+
+import "time"
+
+// SetWarmup 设置启动预热窗口: 从调用时起 skip 时间内, 等级不高于 level 的日志被丢弃并计数,
+// 用于屏蔽启动阶段预期内的错误 (如等待依赖就绪). 窗口结束后恢复正常输出. skip <= 0 关闭预热窗口
+func (log *Logger) SetWarmup(level LogLevel, skip time.Duration) {
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.warmupLevel = level
+	log.warmupUntil = time.Time{}
+	if skip > 0 {
+		log.warmupUntil = log.now().Add(skip)
+	}
+	log.warmupSuppressed.Store(0)
+}
+
+// WarmupSuppressed 返回在预热窗口内被丢弃的日志条数
+func (log *Logger) WarmupSuppressed() int64 {
+	return log.root().warmupSuppressed.Load()
+}
+
+// inWarmup 判断 level 的日志是否处于预热窗口内, 是则计数
+func (log *Logger) inWarmup(level LogLevel) bool {
+	if log.warmupUntil.IsZero() || level > log.warmupLevel || !log.now().Before(log.warmupUntil) {
+		return false
+	}
+	log.warmupSuppressed.Add(1)
+	return true
+}
@@ -0,0 +1,37 @@
+package gologs
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestLogger_SetWarmup(t *testing.T) {
+	var buf bytes.Buffer
+	log := NewLogger(Debug)
+	log.SetOutput(&buf)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	log.SetClock(func() time.Time { return now })
+	log.SuffixFunc = func() string { return "" }
+	log.SetWarmup(Error, 10*time.Second)
+
+	log.Error("waiting for db")
+	log.Named("cache").Warn("not ready")
+	now = now.Add(9 * time.Second)
+	log.Error("still waiting")
+	if buf.Len() != 0 {
+		t.Fatalf("expected warmup messages to be suppressed, got %q", buf.String())
+	}
+	if got := log.WarmupSuppressed(); got != 3 {
+		t.Fatalf("expected 3 suppressed messages, got %d", got)
+	}
+
+	now = now.Add(time.Second)
+	log.Error("db down")
+	if got := buf.String(); got != "[Error] db down \n" {
+		t.Fatalf("expected logging to resume after warmup, got %q", got)
+	}
+	if got := log.WarmupSuppressed(); got != 3 {
+		t.Fatalf("expected suppressed count unchanged, got %d", got)
+	}
+}
@@ -0,0 +1,45 @@
+package gologs
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+)
+
+var (
+	globalWriteLock atomic.Bool
+	globalWriteMu   sync.Mutex
+)
+
+// SetGlobalWriteLock 开启后所有 Logger 的实际写入都经过同一个包级互斥锁, 多个 Logger 共享同一个终端或文件时
+// 不会交错写出半行. 默认关闭, 每个 Logger 只保证自身的写入顺序
+func SetGlobalWriteLock(enable bool) {
+	globalWriteLock.Store(enable)
+}
+
+// lockGlobalWrite 在开启全局写锁时加锁, 返回值需传给 unlockGlobalWrite, 避免期间切换开关导致解锁不匹配
+func lockGlobalWrite() bool {
+	if !globalWriteLock.Load() {
+		return false
+	}
+	globalWriteMu.Lock()
+	return true
+}
+
+func unlockGlobalWrite(locked bool) {
+	if locked {
+		globalWriteMu.Unlock()
+	}
+}
+
+// writeString 将 s 写入 w. 日志文件在 writeFile 中持有 muf 时加全局写锁, 这里不再重复加锁,
+// 否则写文件出错时的错误处理可能再次写日志导致死锁
+func writeString(w io.Writer, s string) {
+	if _, ok := w.(logFileWriter); ok {
+		io.WriteString(w, s)
+		return
+	}
+	locked := lockGlobalWrite()
+	defer unlockGlobalWrite(locked)
+	io.WriteString(w, s)
+}
@@ -0,0 +1,53 @@
+package gologs
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// pieceWriter 将每次写入拆成单字节写入, 没有全局写锁时并发写入会交错
+type pieceWriter struct {
+	buf *bytes.Buffer
+}
+
+func (w pieceWriter) Write(p []byte) (int, error) {
+	for _, c := range p {
+		w.buf.WriteByte(c)
+	}
+	return len(p), nil
+}
+
+func TestSetGlobalWriteLock(t *testing.T) {
+	SetGlobalWriteLock(true)
+	t.Cleanup(func() { SetGlobalWriteLock(false) })
+
+	var buf bytes.Buffer
+	out := pieceWriter{&buf}
+	a, b := NewLogger(Debug), NewLogger(Debug)
+	a.SetOutput(out)
+	b.SetOutput(out)
+
+	var wg sync.WaitGroup
+	for _, log := range []*Logger{a, b, a, b} {
+		wg.Add(1)
+		go func(log *Logger) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				log.Warn("a fairly long line that would tear if writes interleaved")
+			}
+		}(log)
+	}
+	wg.Wait()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 800 {
+		t.Fatalf("expected 800 lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if line != "[Warn] a fairly long line that would tear if writes interleaved " {
+			t.Fatalf("torn line: %q", line)
+		}
+	}
+}